@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+)
+
+const commandUsage = `用法:
+  api                                   启动 HTTP 服务
+  api articles export [-format jsonl|markdown] [-author ID] [-out 文件]
+  api articles import -file 文件 [-format jsonl|markdown] [-author ID] [-dry-run]
+`
+
+// runCommand 执行命令行子命令，返回进程退出码
+func runCommand(args []string, transferService service.TransferService) int {
+	if len(args) < 2 || args[0] != "articles" {
+		fmt.Fprint(os.Stderr, commandUsage)
+		return 2
+	}
+
+	// Ctrl+C 时取消正在执行的查询
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch args[1] {
+	case "export":
+		return exportArticles(ctx, args[2:], transferService)
+	case "import":
+		return importArticles(ctx, args[2:], transferService)
+	default:
+		fmt.Fprint(os.Stderr, commandUsage)
+		return 2
+	}
+}
+
+func exportArticles(ctx context.Context, args []string, transferService service.TransferService) int {
+	fs := flag.NewFlagSet("articles export", flag.ContinueOnError)
+	format := fs.String("format", "jsonl", "导出格式：jsonl 或 markdown（zip）")
+	author := fs.Uint("author", 0, "只导出指定作者的文章，0 表示全部")
+	out := fs.String("out", "", "输出文件，默认为 articles.jsonl 或 articles.zip")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// 数据库日志输出到标准输出，因此导出内容总是写入文件
+	if *out == "" {
+		*out = "articles.jsonl"
+		if *format == domain.TransferFormatMarkdown {
+			*out = "articles.zip"
+		}
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	n, err := transferService.ExportArticles(ctx, f, *format, uint(*author))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export failed after %d article(s): %v\n", n, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "exported %d article(s) to %s\n", n, *out)
+	return 0
+}
+
+func importArticles(ctx context.Context, args []string, transferService service.TransferService) int {
+	fs := flag.NewFlagSet("articles import", flag.ContinueOnError)
+	file := fs.String("file", "", "导入文件（.jsonl 或 .zip）")
+	format := fs.String("format", "", "导入格式：jsonl 或 markdown，默认根据扩展名判断")
+	author := fs.Uint("author", 0, "未指定 author_id 的记录使用的作者")
+	dryRun := fs.Bool("dry-run", false, "只校验并输出结果，不写入数据库")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fs.Usage()
+		return 2
+	}
+	if *format == "" {
+		*format = service.TransferFormatFromFileName(*file)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report, err := transferService.ImportArticles(ctx, f, info.Size(), *format, service.ImportOptions{
+		AuthorID:    uint(*author),
+		AllowAuthor: true,
+		DryRun:      *dryRun,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,10 +14,14 @@ import (
 	"github.com/Anning01/user-management/internal/api"
 	"github.com/Anning01/user-management/internal/api/handlers"
 	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/i18n"
+	"github.com/Anning01/user-management/internal/metrics"
 	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
 	"github.com/Anning01/user-management/internal/service"
 	"github.com/Anning01/user-management/migrations"
 	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -36,53 +42,173 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// 打印配置信息（用于调试，不显示敏感信息）
-	log.Printf("Configuration loaded:")
-	log.Printf("  Server Port: %s", cfg.Server.Port)
-	log.Printf("  Database Host: %s", cfg.Database.Host)
-	log.Printf("  Database Port: %s", cfg.Database.Port)
-	log.Printf("  Database Name: %s", cfg.Database.Name)
-	log.Printf("  Database Username: %s", cfg.Database.Username)
-	if cfg.Database.Password == "" {
-		log.Printf("  Database Password: (empty) - connecting without password")
-	} else {
-		log.Printf("  Database Password: (set, length: %d)", len(cfg.Database.Password))
+	// 初始化日志
+	if err := logger.Init(logger.Options{Level: cfg.Log.Level, Format: cfg.Log.Format}); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
 	}
+	ctx := context.Background()
+
+	// 打印配置信息（用于调试，不显示敏感信息）
+	logger.Info(ctx, "configuration loaded",
+		"port", cfg.Server.Port,
+		slog.Group("database",
+			"driver", cfg.Database.Driver,
+			"host", cfg.Database.Host,
+			"port", cfg.Database.Port,
+			"name", cfg.Database.Name,
+			"username", cfg.Database.Username,
+			"password_set", cfg.Database.Password != "",
+		),
+		"log_level", cfg.Log.Level,
+	)
 	if cfg.JWT.SecretKey == "" {
-		log.Printf("  ⚠️  WARNING: JWT Secret Key is empty! This is insecure for production!")
-	} else {
-		log.Printf("  JWT Secret Key: (set, length: %d)", len(cfg.JWT.SecretKey))
+		logger.Warn(ctx, "JWT secret key is empty, this is insecure for production")
 	}
 
-	// 初始化日志
-	logger.Init()
-
 	// 连接数据库
 	db, err := repository.NewDBConnection(&cfg.Database)
 	if err != nil {
-		logger.Fatalf("Failed to connect to database: %v", err)
+		logger.Fatal(ctx, "failed to connect to database", logger.Err(err))
 	}
 
 	// 运行数据库迁移
 	if err := migrations.Migrate(db); err != nil {
-		logger.Fatalf("Migration failed: %v", err)
+		logger.Fatal(ctx, "migration failed", logger.Err(err))
+	}
+
+	// 初始化指标
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+		sqlDB, err := db.DB()
+		if err != nil {
+			logger.Fatal(ctx, "failed to get database handle", logger.Err(err))
+		}
+		if err := appMetrics.RegisterDB(sqlDB, cfg.Database.Name); err != nil {
+			logger.Fatal(ctx, "failed to register database metrics", logger.Err(err))
+		}
 	}
 
 	// 初始化存储库
 	userRepo := repository.NewUserRepository(db)
 	articleRepo := repository.NewArticleRepository(db)
+	contributorRepo := repository.NewContributorRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	engagementRepo := repository.NewEngagementRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	privacyRepo := repository.NewPrivacyRepository(db)
+	txManager := repository.NewTxManager(db)
+
+	// 初始化搜索索引
+	searchIndex, err := search.NewIndex(cfg.Search.Backend, db)
+	if err != nil {
+		logger.Fatal(ctx, "failed to initialize search index", logger.Err(err))
+	}
+
+	// 初始化文件存储
+	// 下载地址签名与 JWT 使用不同的密钥，未配置时为当前进程随机生成，避免使用空密钥
+	signer := storage.NewSigner(cfg.Storage.SigningKey)
+	if cfg.Storage.SigningKey == "" {
+		if signer, err = storage.NewRandomSigner(); err != nil {
+			logger.Fatal(ctx, "failed to generate storage signing key", logger.Err(err))
+		}
+		logger.Warn(ctx, "storage signing key is empty, using a random key; signed download URLs will not survive restarts")
+	}
+	store, err := storage.New(storage.Options{
+		Driver:    cfg.Storage.Driver,
+		LocalDir:  cfg.Storage.LocalDir,
+		PublicURL: cfg.Storage.PublicURL,
+		Signer:    signer,
+		Endpoint:  cfg.Storage.S3.Endpoint,
+		Region:    cfg.Storage.S3.Region,
+		Bucket:    cfg.Storage.S3.Bucket,
+		AccessKey: cfg.Storage.S3.AccessKey,
+		SecretKey: cfg.Storage.S3.SecretKey,
+		UseSSL:    cfg.Storage.S3.UseSSL,
+	})
+	if err != nil {
+		logger.Fatal(ctx, "failed to initialize storage", logger.Err(err))
+	}
+
+	// 命令行子命令，如 articles export / articles import
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], service.NewTransferService(articleRepo, userRepo, searchIndex)))
+	}
 
 	// 初始化服务
-	userService := service.NewUserService(userRepo)
-	articleService := service.NewArticleService(articleRepo, userRepo)
+	userService := service.NewUserService(userRepo, articleRepo, searchIndex, txManager, appMetrics)
+	articleService := service.NewArticleService(articleRepo, contributorRepo, seriesRepo, userRepo, txManager, searchIndex)
+	seriesService := service.NewSeriesService(seriesRepo, articleRepo, contributorRepo, userRepo)
+	commentService := service.NewCommentService(commentRepo, articleRepo, contributorRepo, userRepo, txManager)
+	followService := service.NewFollowService(followRepo, userRepo)
+
+	urlExpiry := cfg.Storage.URLExpiry * time.Minute
+	maxUploadSize := cfg.Upload.MaxSize << 20
+	maxAvatarSize := cfg.Upload.AvatarMaxSize << 20
+	attachmentService := service.NewAttachmentService(attachmentRepo, articleRepo, contributorRepo, store,
+		service.UploadLimits{MaxSize: maxUploadSize, AllowedTypes: cfg.Upload.AllowedTypes}, urlExpiry)
+	avatarService := service.NewAvatarService(userRepo, store,
+		service.UploadLimits{MaxSize: maxAvatarSize, AllowedTypes: service.AvatarTypes}, cfg.Upload.AvatarThumbSize, urlExpiry)
+
+	// 浏览量在内存中缓冲，定期批量写入
+	viewCounter := service.NewViewCounter(engagementRepo, cfg.Views.FlushInterval*time.Second, cfg.Views.DedupWindow*time.Minute)
+	viewCounter.Start()
+	engagementService := service.NewEngagementService(engagementRepo, articleRepo, viewCounter)
+
+	// 回收站中超过保留期的数据定期彻底删除
+	trashService := service.NewTrashService(trashRepo, articleRepo, userRepo, searchIndex, store)
+	retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+	var trashPurger *service.TrashPurger
+	if retention > 0 {
+		trashPurger = service.NewTrashPurger(trashService, retention, cfg.Trash.PurgeInterval*time.Hour)
+		trashPurger.Start()
+	}
+
+	// 个人数据导出在后台生成，注销请求在冷静期结束后执行
+	privacyService := service.NewPrivacyService(privacyRepo, userRepo, searchIndex, store,
+		time.Duration(cfg.Privacy.CoolingOffDays)*24*time.Hour, cfg.Privacy.ExportExpiry*time.Hour, urlExpiry)
+	privacyWorker := service.NewPrivacyWorker(privacyService, cfg.Privacy.CheckInterval*time.Minute)
+	privacyWorker.Start()
+
+	transferService := service.NewTransferService(articleRepo, userRepo, searchIndex)
+
+	// 内存索引不持久化，启动时从数据库重建
+	if cfg.Search.Backend == search.BackendMemory {
+		if err := articleService.RebuildSearchIndex(context.Background()); err != nil {
+			logger.Fatal(ctx, "failed to build search index", logger.Err(err))
+		}
+	}
 
 	// 初始化处理器
-	userHandler := handlers.NewUserHandler(userService, &cfg.JWT)
-	articleHandler := handlers.NewArticleHandler(articleService)
+	userHandler := handlers.NewUserHandler(userService, followService, &cfg.JWT)
+	articleHandler := handlers.NewArticleHandler(articleService, engagementService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	engagementHandler := handlers.NewEngagementHandler(engagementService)
+	followHandler := handlers.NewFollowHandler(followService, userService)
+	feedHandler := handlers.NewFeedHandler(articleService, userService, &cfg.Feed)
+	uploadHandler := handlers.NewUploadHandler(attachmentService, avatarService, store, signer, maxUploadSize, maxAvatarSize)
+	trashHandler := handlers.NewTrashHandler(trashService, retention)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	transferHandler := handlers.NewTransferHandler(transferService, cfg.Upload.ImportMaxSize<<20)
+	seriesHandler := handlers.NewSeriesHandler(seriesService)
+
+	// 加载提示文案的消息目录
+	bundle, err := i18n.NewBundle(cfg.I18n.DefaultLocale, cfg.I18n.Dir)
+	if err != nil {
+		logger.Fatal(ctx, "failed to load message catalog", logger.Err(err))
+	}
 
 	// 设置路由
-	r := gin.Default()
-	api.SetupRoutes(r, userHandler, articleHandler, &cfg.JWT)
+	// 不使用 gin 默认的日志与恢复中间件，由 SetupRoutes 注册结构化的访问日志与 panic 恢复
+	r := gin.New()
+	api.SetupRoutes(r, userHandler, articleHandler, commentHandler, engagementHandler, followHandler, feedHandler, uploadHandler, trashHandler, privacyHandler, transferHandler, seriesHandler, userService, &cfg.JWT, &cfg.Log.Access, &cfg.Metrics, appMetrics, bundle)
+
+	// 所有请求上下文都派生自 baseCtx，关闭超时后取消它以中断仍在执行的查询
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 
 	// 创建服务器
 	srv := &http.Server{
@@ -91,27 +217,63 @@ func main() {
 		ReadTimeout:    cfg.Server.ReadTimeout * time.Second,
 		WriteTimeout:   cfg.Server.WriteTimeout * time.Second,
 		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+		BaseContext:    func(net.Listener) context.Context { return baseCtx },
 	}
 
 	// 启动服务器（非阻塞）
 	go func() {
-		logger.Infof("Server is running on port %s", cfg.Server.Port)
+		logger.Info(ctx, "server is running", "port", cfg.Server.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatalf("listen: %s\n", err)
+			logger.Fatal(ctx, "server failed to listen", logger.Err(err))
 		}
 	}()
 
+	// 配置了管理端口时单独提供指标，避免暴露在公网端口上
+	var metricsSrv *http.Server
+	if appMetrics != nil && cfg.Metrics.Port != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", appMetrics.Handler())
+		metricsSrv = &http.Server{
+			Addr:        ":" + cfg.Metrics.Port,
+			Handler:     mux,
+			ReadTimeout: cfg.Server.ReadTimeout * time.Second,
+		}
+		go func() {
+			logger.Info(ctx, "metrics server is running", "port", cfg.Metrics.Port)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal(ctx, "metrics server failed to listen", logger.Err(err))
+			}
+		}()
+	}
+
 	// 优雅关闭
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	logger.Info("Shutting down server...")
+	logger.Info(ctx, "shutting down server")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to shutdown:", err)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error(ctx, "server forced to shutdown", logger.Err(err))
+		cancelRequests()
+		srv.Close()
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			metricsSrv.Close()
+		}
+	}
+
+	if trashPurger != nil {
+		trashPurger.Stop()
+	}
+	privacyWorker.Stop()
+
+	// 写入尚未刷新的浏览量
+	if err := viewCounter.Stop(); err != nil {
+		logger.Error(ctx, "failed to flush view counts", logger.Err(err))
 	}
 
-	logger.Info("Server exiting")
+	logger.Info(ctx, "server exiting")
 }
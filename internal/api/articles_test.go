@@ -0,0 +1,438 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+	"github.com/Anning01/user-management/pkg/slug"
+)
+
+// articlePath 返回文章接口地址，suffix 为文章下的子路径
+func articlePath(id uint, suffix string) string {
+	return fmt.Sprintf("/api/v1/articles/%d%s", id, suffix)
+}
+
+// articleList 列表接口的响应
+type articleList struct {
+	Articles   []domain.Article `json:"articles"`
+	Total      int64            `json:"total"`
+	NextCursor string           `json:"next_cursor"`
+	PrevCursor string           `json:"prev_cursor"`
+	HasMore    bool             `json:"has_more"`
+}
+
+func listArticles(t *testing.T, c *testutil.Client, path string) articleList {
+	t.Helper()
+	var list articleList
+	c.Get(t, path).Expect(t, http.StatusOK).Decode(t, &list)
+	return list
+}
+
+func TestCreateArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	c := s.Login(t, author)
+
+	var created struct {
+		Article domain.Article `json:"article"`
+	}
+	c.Post(t, "/api/v1/articles", map[string]string{
+		"title":   "Hello World",
+		"content": "The first article of the blog.",
+	}).Expect(t, http.StatusCreated).Decode(t, &created)
+	a := created.Article
+	if a.ID == 0 || a.Slug != "hello-world" || a.Status != domain.ArticleStatusPublished || a.AuthorID != author.ID {
+		t.Fatalf("unexpected article: %+v", a)
+	}
+	if a.ContentFormat != "plain" || a.Excerpt == "" {
+		t.Fatalf("expected default format and excerpt: %+v", a)
+	}
+
+	// 标题相同时自动生成不冲突的 slug
+	var second struct {
+		Article domain.Article `json:"article"`
+	}
+	c.Post(t, "/api/v1/articles", map[string]string{
+		"title":   "Hello World",
+		"content": "Another article with the same title.",
+	}).Expect(t, http.StatusCreated).Decode(t, &second)
+	if second.Article.Slug == a.Slug {
+		t.Fatalf("expected unique slug, got %q twice", a.Slug)
+	}
+
+	tests := []struct {
+		name   string
+		body   map[string]string
+		status int
+		error  string
+	}{
+		{"short title", map[string]string{"title": "Hi", "content": "Long enough content."}, http.StatusBadRequest, ""},
+		{"short content", map[string]string{"title": "Title", "content": "short"}, http.StatusBadRequest, ""},
+		{"bad format", map[string]string{"title": "Title", "content": "Long enough content.", "content_format": "html"}, http.StatusBadRequest, ""},
+		{"bad status", map[string]string{"title": "Title", "content": "Long enough content.", "status": "archived"}, http.StatusBadRequest, ""},
+		{"invalid slug", map[string]string{"title": "Title", "content": "Long enough content.", "slug": "Not A Slug!"}, http.StatusBadRequest, "invalid slug"},
+		{"slug in use", map[string]string{"title": "Title", "content": "Long enough content.", "slug": "hello-world"}, http.StatusConflict, "slug already in use"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := c.Post(t, "/api/v1/articles", tt.body).Expect(t, tt.status)
+			if tt.error != "" {
+				resp.ExpectError(t, tt.status, tt.error)
+			}
+		})
+	}
+
+	s.Client().Post(t, "/api/v1/articles", map[string]string{
+		"title":   "Anonymous",
+		"content": "Anonymous users cannot post.",
+	}).Expect(t, http.StatusUnauthorized)
+}
+
+func TestGetArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author, testutil.WithTitle("Readable Article"))
+	c := s.Client()
+
+	var got domain.Article
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if got.Title != "Readable Article" || got.Author.Username != author.Username {
+		t.Fatalf("unexpected article: %+v", got)
+	}
+	if got.LikedByMe != nil {
+		t.Fatal("anonymous response should not contain liked_by_me")
+	}
+
+	c.Get(t, "/api/v1/articles/abc").ExpectError(t, http.StatusBadRequest, "invalid article id")
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "article not found")
+
+	c.Get(t, "/api/v1/articles/by-slug/"+article.Slug).Expect(t, http.StatusOK)
+	c.Get(t, "/api/v1/articles/by-slug/missing").ExpectError(t, http.StatusNotFound, "article not found")
+}
+
+func TestRenderArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	c := s.Login(t, author)
+
+	var created struct {
+		Article domain.Article `json:"article"`
+	}
+	c.Post(t, "/api/v1/articles", map[string]string{
+		"title":          "Markdown Article",
+		"content":        "# Heading\n\nSome *text* <script>alert(1)</script>",
+		"content_format": "markdown",
+	}).Expect(t, http.StatusCreated).Decode(t, &created)
+
+	var plain domain.Article
+	c.Get(t, articlePath(created.Article.ID, "")).Expect(t, http.StatusOK).Decode(t, &plain)
+	if plain.RenderedHTML != "" {
+		t.Fatal("rendered_html should only be returned with render=html")
+	}
+
+	var rendered domain.Article
+	c.Get(t, articlePath(created.Article.ID, "?render=html")).Expect(t, http.StatusOK).Decode(t, &rendered)
+	if rendered.RenderedHTML == "" || len(rendered.TOC) != 1 {
+		t.Fatalf("expected rendered html and toc: %+v", rendered)
+	}
+	if strings.Contains(rendered.RenderedHTML, "<script>") {
+		t.Fatalf("rendered html is not sanitized: %s", rendered.RenderedHTML)
+	}
+}
+
+func TestUpdateArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	other := s.CreateUser(t)
+	article := s.CreateArticle(t, author, testutil.WithTitle("Original Title"))
+	c := s.Login(t, author)
+
+	body := map[string]string{
+		"title":   "Updated Title",
+		"content": "Updated content of the article.",
+		"slug":    "updated-title",
+	}
+	s.Login(t, other).Put(t, articlePath(article.ID, ""), body).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Put(t, "/api/v1/articles/999", body).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Put(t, "/api/v1/articles/abc", body).ExpectError(t, http.StatusBadRequest, "invalid article id")
+	c.Put(t, articlePath(article.ID, ""), map[string]string{"title": "Updated Title"}).Expect(t, http.StatusBadRequest)
+
+	c.Put(t, articlePath(article.ID, ""), body).Expect(t, http.StatusOK)
+
+	var got domain.Article
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if got.Title != "Updated Title" || got.Slug != "updated-title" {
+		t.Fatalf("article not updated: %+v", got)
+	}
+
+	// 旧 slug 重定向到新 slug，并保留查询参数
+	resp := c.Get(t, "/api/v1/articles/by-slug/"+article.Slug+"?render=html").Expect(t, http.StatusMovedPermanently)
+	if loc := resp.Header.Get("Location"); loc != "/api/v1/articles/by-slug/updated-title?render=html" {
+		t.Fatalf("unexpected redirect location %q", loc)
+	}
+
+	taken := s.CreateArticle(t, author)
+	body["slug"] = taken.Slug
+	c.Put(t, articlePath(article.ID, ""), body).ExpectError(t, http.StatusConflict, "slug already in use")
+}
+
+func TestDeleteArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	other := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+
+	s.Login(t, other).Delete(t, articlePath(article.ID, "")).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Delete(t, "/api/v1/articles/abc").ExpectError(t, http.StatusBadRequest, "invalid article id")
+
+	c.Delete(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	c.Get(t, articlePath(article.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Delete(t, articlePath(article.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+}
+
+func TestDraftArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	other := s.CreateUser(t)
+	published := s.CreateArticle(t, author)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c := s.Login(t, author)
+
+	s.Client().Get(t, articlePath(draft.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+	s.Login(t, other).Get(t, articlePath(draft.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+	s.Client().Get(t, "/api/v1/articles/by-slug/"+draft.Slug).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Get(t, articlePath(draft.ID, "")).Expect(t, http.StatusOK)
+
+	if list := listArticles(t, s.Client(), "/api/v1/articles"); list.Total != 1 || list.Articles[0].ID != published.ID {
+		t.Fatalf("public list should only contain published articles: %+v", list)
+	}
+	if list := listArticles(t, c, "/api/v1/users/me/articles"); list.Total != 2 {
+		t.Fatalf("expected 2 articles of my own, got %d", list.Total)
+	}
+	list := listArticles(t, c, "/api/v1/users/me/articles?filter[status]=draft")
+	if list.Total != 1 || list.Articles[0].ID != draft.ID {
+		t.Fatalf("unexpected draft list: %+v", list)
+	}
+
+	// 发布草稿后对所有人可见
+	c.Put(t, articlePath(draft.ID, ""), map[string]string{
+		"title":   draft.Title,
+		"content": draft.Content,
+		"status":  domain.ArticleStatusPublished,
+	}).Expect(t, http.StatusOK)
+	s.Client().Get(t, articlePath(draft.ID, "")).Expect(t, http.StatusOK)
+}
+
+func TestListArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+	bob := s.CreateUser(t)
+	for i := 0; i < 3; i++ {
+		s.CreateArticle(t, alice)
+	}
+	s.CreateArticle(t, bob, testutil.WithTitle("Zebra Article"))
+	c := s.Client()
+
+	first := listArticles(t, c, "/api/v1/articles?page=1&page_size=3")
+	if first.Total != 4 || len(first.Articles) != 3 || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	last := listArticles(t, c, "/api/v1/articles?page=2&page_size=3")
+	if len(last.Articles) != 1 || last.NextCursor != "" {
+		t.Fatalf("unexpected last page: %+v", last)
+	}
+
+	// 页码分页返回的游标可以继续用于游标分页
+	next := listArticles(t, c, "/api/v1/articles?page_size=3&after="+url.QueryEscape(first.NextCursor))
+	if len(next.Articles) != 1 || next.HasMore || next.Articles[0].ID != last.Articles[0].ID {
+		t.Fatalf("unexpected cursor page: %+v", next)
+	}
+	prev := listArticles(t, c, "/api/v1/articles?page_size=3&before="+url.QueryEscape(next.PrevCursor))
+	if len(prev.Articles) != 3 {
+		t.Fatalf("unexpected previous page: %+v", prev)
+	}
+
+	sorted := listArticles(t, c, "/api/v1/articles?sort=-title")
+	if sorted.Articles[0].Title != "Zebra Article" {
+		t.Fatalf("expected sort by title desc, got %q first", sorted.Articles[0].Title)
+	}
+	// 游标只适用于默认排序，指定 sort 时不返回游标
+	if sorted = listArticles(t, c, "/api/v1/articles?sort=-title&page_size=1"); sorted.NextCursor != "" {
+		t.Fatalf("expected no cursor with sort, got %q", sorted.NextCursor)
+	}
+	filtered := listArticles(t, c, fmt.Sprintf("/api/v1/articles?filter[author_id]=%d", bob.ID))
+	if filtered.Total != 1 || filtered.Articles[0].AuthorID != bob.ID {
+		t.Fatalf("unexpected filtered list: %+v", filtered)
+	}
+
+	var projected struct {
+		Articles []map[string]any `json:"articles"`
+	}
+	c.Get(t, "/api/v1/articles?fields=id,title").Expect(t, http.StatusOK).Decode(t, &projected)
+	if len(projected.Articles[0]) != 2 {
+		t.Fatalf("expected only id and title, got %v", projected.Articles[0])
+	}
+
+	errorCases := map[string]string{
+		"/api/v1/articles?sort=password":                                             "unsupported sort field: password",
+		"/api/v1/articles?filter[password]=x":                                        "unsupported filter: password",
+		"/api/v1/articles?filter[author_id]=abc":                                     "invalid value for filter author_id",
+		"/api/v1/articles?fields=password":                                           "unsupported field: password",
+		"/api/v1/articles?after=invalid":                                             "invalid cursor",
+		"/api/v1/articles?after=" + first.NextCursor + "&sort=title":                 "sort cannot be combined with cursor pagination",
+		"/api/v1/articles?after=" + first.NextCursor + "&before=" + first.NextCursor: "after and before cannot be used together",
+	}
+	for path, message := range errorCases {
+		c.Get(t, path).ExpectError(t, http.StatusBadRequest, message)
+	}
+
+	mine := listArticles(t, s.Login(t, alice), "/api/v1/users/me/articles?page_size=2&after="+url.QueryEscape(first.NextCursor))
+	if len(mine.Articles) != 1 || mine.Articles[0].ID != last.Articles[0].ID || mine.HasMore {
+		t.Fatalf("unexpected cursor page of my articles: %+v", mine)
+	}
+	s.Client().Get(t, "/api/v1/users/me/articles").Expect(t, http.StatusUnauthorized)
+}
+
+func TestSearchArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+	bob := s.CreateUser(t)
+	s.CreateArticle(t, alice, testutil.WithTitle("Learning Golang"), testutil.WithContent("Goroutines and channels explained."))
+	tips := s.CreateArticle(t, bob, testutil.WithTitle("Golang Tips"), testutil.WithContent("A few tips for writing better code."))
+	s.CreateArticle(t, bob, testutil.WithTitle("Golang Drafts"), testutil.WithContent("Unpublished thoughts."), testutil.AsDraft())
+	c := s.Client()
+
+	var results struct {
+		Results []domain.ArticleSearchResult `json:"results"`
+		Total   int64                        `json:"total"`
+	}
+	c.Get(t, "/api/v1/articles/search?q=golang").Expect(t, http.StatusOK).Decode(t, &results)
+	if results.Total != 2 {
+		t.Fatalf("expected 2 published results, got %+v", results)
+	}
+
+	c.Get(t, fmt.Sprintf("/api/v1/articles/search?q=golang&author_id=%d", alice.ID)).Expect(t, http.StatusOK).Decode(t, &results)
+	if results.Total != 1 || results.Results[0].Article.AuthorID != alice.ID {
+		t.Fatalf("unexpected author filtered results: %+v", results)
+	}
+	c.Get(t, "/api/v1/articles/search?q=golang&created_after=2999-01-01").Expect(t, http.StatusOK).Decode(t, &results)
+	if results.Total != 0 {
+		t.Fatalf("expected no results created in the future, got %d", results.Total)
+	}
+
+	// 绕过服务直接转为草稿，索引中仍有该文章，结果与总数都应排除
+	if err := s.DB.Model(&domain.Article{}).Where("id = ?", tips.ID).
+		Update("status", domain.ArticleStatusDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	c.Get(t, "/api/v1/articles/search?q=golang").Expect(t, http.StatusOK).Decode(t, &results)
+	if results.Total != 1 || len(results.Results) != 1 || results.Results[0].Article.ID == tips.ID {
+		t.Fatalf("expected stale index entry to be excluded, got %+v", results)
+	}
+
+	c.Get(t, "/api/v1/articles/search").ExpectError(t, http.StatusBadRequest, "query parameter q is required")
+	c.Get(t, "/api/v1/articles/search?q=go&author_id=abc").ExpectError(t, http.StatusBadRequest, "invalid author_id")
+	c.Get(t, "/api/v1/articles/search?q=go&created_after=yesterday").ExpectError(t, http.StatusBadRequest, "invalid created_after")
+	c.Get(t, "/api/v1/articles/search?q=go&created_before=tomorrow").ExpectError(t, http.StatusBadRequest, "invalid created_before")
+}
+
+func TestFollowFeed(t *testing.T) {
+	s := testutil.NewServer(t)
+	reader := s.CreateUser(t)
+	followed := s.CreateUser(t)
+	stranger := s.CreateUser(t)
+	article := s.CreateArticle(t, followed)
+	s.CreateArticle(t, followed, testutil.AsDraft())
+	s.CreateArticle(t, stranger)
+	c := s.Login(t, reader)
+
+	if feed := listArticles(t, c, "/api/v1/users/me/feed"); len(feed.Articles) != 0 {
+		t.Fatalf("expected empty feed, got %+v", feed)
+	}
+
+	c.Post(t, fmt.Sprintf("/api/v1/users/%d/follow", followed.ID), nil).Expect(t, http.StatusOK)
+	feed := listArticles(t, c, "/api/v1/users/me/feed")
+	if len(feed.Articles) != 1 || feed.Articles[0].ID != article.ID {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+
+	c.Get(t, "/api/v1/users/me/feed?after=invalid").ExpectError(t, http.StatusBadRequest, "invalid cursor")
+	s.Client().Get(t, "/api/v1/users/me/feed").Expect(t, http.StatusUnauthorized)
+}
+
+func TestCursorPaginationTimeZone(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("UTC+8", 8*60*60)
+
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	for i := 0; i < 3; i++ {
+		s.CreateArticle(t, author)
+	}
+
+	// 进程时区变化后，之前写入的记录与游标仍能正确比较
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	c := s.Client()
+	seen := map[uint]bool{}
+	path := "/api/v1/articles?page_size=1"
+	for {
+		page := listArticles(t, c, path)
+		for _, article := range page.Articles {
+			if seen[article.ID] {
+				t.Fatalf("article %d returned twice", article.ID)
+			}
+			seen[article.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		path = "/api/v1/articles?page_size=1&after=" + url.QueryEscape(page.NextCursor)
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 articles across pages, got %d", len(seen))
+	}
+}
+
+func TestArticleSlugCollisions(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+
+	check := func(title string, n int) []string {
+		t.Helper()
+		seen := map[string]bool{}
+		var slugs []string
+		for i := 0; i < n; i++ {
+			article := s.CreateArticle(t, author, testutil.WithTitle(title))
+			if !slug.Valid(article.Slug) || seen[article.Slug] {
+				t.Fatalf("invalid or duplicate slug %q for %q", article.Slug, title)
+			}
+			seen[article.Slug] = true
+			slugs = append(slugs, article.Slug)
+		}
+		return slugs
+	}
+
+	// 无法转写的标题使用默认 slug，冲突后直接使用随机后缀
+	slugs := check("こんにちは", 3)
+	if slugs[0] != "article" || !strings.HasPrefix(slugs[1], "article-") || slugs[1] == "article-2" {
+		t.Fatalf("unexpected fallback slugs: %v", slugs)
+	}
+
+	// 追加后缀时截断过长的 slug，多次冲突后改用随机后缀
+	long := strings.TrimSpace(strings.Repeat("word ", 16))
+	slugs = check(long, 12)
+	if slugs[0] != strings.ReplaceAll(long, " ", "-") || !strings.HasSuffix(slugs[1], "-2") || !strings.HasSuffix(slugs[9], "-10") {
+		t.Fatalf("unexpected numbered slugs: %v", slugs)
+	}
+	if strings.HasSuffix(slugs[10], "-11") {
+		t.Fatalf("expected random suffix after numbered attempts: %v", slugs)
+	}
+}
@@ -0,0 +1,182 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+type commentList struct {
+	Comments []*domain.Comment `json:"comments"`
+}
+
+func createComment(t *testing.T, c *testutil.Client, articleID uint, body map[string]any) *domain.Comment {
+	t.Helper()
+	var created struct {
+		Comment domain.Comment `json:"comment"`
+	}
+	c.Post(t, articlePath(articleID, "/comments"), body).Expect(t, http.StatusCreated).Decode(t, &created)
+	return &created.Comment
+}
+
+func TestComments(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	reader := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, reader)
+
+	root := createComment(t, c, article.ID, map[string]any{"content": "Great article!"})
+	reply := createComment(t, s.Login(t, author), article.ID, map[string]any{"content": "Thanks!", "parent_id": root.ID})
+
+	var list commentList
+	s.Client().Get(t, articlePath(article.ID, "/comments")).Expect(t, http.StatusOK).Decode(t, &list)
+	if len(list.Comments) != 1 || len(list.Comments[0].Replies) != 1 || list.Comments[0].Replies[0].ID != reply.ID {
+		t.Fatalf("unexpected comment tree: %+v", list.Comments)
+	}
+
+	var got domain.Article
+	s.Client().Get(t, articlePath(article.ID, "")).Decode(t, &got)
+	if got.CommentCount != 2 {
+		t.Fatalf("expected comment_count 2, got %d", got.CommentCount)
+	}
+
+	// 删除有回复的评论时保留占位节点
+	c.Delete(t, articlePath(article.ID, fmt.Sprintf("/comments/%d", root.ID))).Expect(t, http.StatusOK)
+	s.Client().Get(t, articlePath(article.ID, "/comments")).Decode(t, &list)
+	if len(list.Comments) != 1 || !list.Comments[0].Deleted || list.Comments[0].Content != "" {
+		t.Fatalf("expected deleted placeholder: %+v", list.Comments[0])
+	}
+}
+
+func TestCommentErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	reader := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	other := s.CreateArticle(t, author)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c := s.Login(t, reader)
+	comment := createComment(t, c, article.ID, map[string]any{"content": "First!"})
+	commentPath := articlePath(article.ID, fmt.Sprintf("/comments/%d", comment.ID))
+
+	c.Get(t, "/api/v1/articles/abc/comments").ExpectError(t, http.StatusBadRequest, "invalid article id")
+	c.Get(t, "/api/v1/articles/999/comments").ExpectError(t, http.StatusNotFound, "article not found")
+	c.Get(t, articlePath(draft.ID, "/comments")).ExpectError(t, http.StatusNotFound, "article not found")
+
+	c.Post(t, articlePath(article.ID, "/comments"), map[string]any{"content": ""}).Expect(t, http.StatusBadRequest)
+	c.Post(t, articlePath(draft.ID, "/comments"), map[string]any{"content": "Hi"}).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Post(t, articlePath(other.ID, "/comments"), map[string]any{"content": "Hi", "parent_id": comment.ID}).
+		ExpectError(t, http.StatusBadRequest, "parent comment not found")
+	s.Client().Post(t, articlePath(article.ID, "/comments"), map[string]any{"content": "Hi"}).Expect(t, http.StatusUnauthorized)
+
+	// 只有评论作者可以修改评论
+	s.Login(t, author).Put(t, commentPath, map[string]any{"content": "Edited"}).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Put(t, articlePath(other.ID, fmt.Sprintf("/comments/%d", comment.ID)), map[string]any{"content": "Edited"}).
+		ExpectError(t, http.StatusNotFound, "comment not found")
+	c.Put(t, articlePath(article.ID, "/comments/abc"), map[string]any{"content": "Edited"}).
+		ExpectError(t, http.StatusBadRequest, "invalid comment id")
+	c.Put(t, commentPath, map[string]any{"content": ""}).Expect(t, http.StatusBadRequest)
+	updated := c.Put(t, commentPath, map[string]any{"content": "Edited"}).Expect(t, http.StatusOK).JSON(t)
+	if updated["comment"].(map[string]any)["content"] != "Edited" {
+		t.Fatalf("comment not updated: %v", updated)
+	}
+
+	// 其他读者不能删除评论，文章作者可以
+	stranger := s.Login(t, s.CreateUser(t))
+	stranger.Delete(t, commentPath).ExpectError(t, http.StatusForbidden, "permission denied")
+	s.Login(t, author).Delete(t, commentPath).Expect(t, http.StatusOK)
+	c.Delete(t, commentPath).ExpectError(t, http.StatusNotFound, "comment not found")
+}
+
+func TestCommentModeration(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	reader := s.CreateUser(t)
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, reader)
+
+	kept := createComment(t, c, article.ID, map[string]any{"content": "A fair comment"})
+	spam := createComment(t, c, article.ID, map[string]any{"content": "Buy cheap stuff"})
+
+	flagger := s.Login(t, author)
+	flagger.Post(t, articlePath(article.ID, fmt.Sprintf("/comments/%d/flag", kept.ID)), nil).Expect(t, http.StatusOK)
+	flagger.Post(t, articlePath(article.ID, fmt.Sprintf("/comments/%d/flag", spam.ID)), map[string]string{"reason": "spam"}).Expect(t, http.StatusOK)
+	flagger.Post(t, articlePath(article.ID, "/comments/999/flag"), nil).ExpectError(t, http.StatusNotFound, "comment not found")
+
+	// 审核接口仅管理员可用
+	c.Get(t, "/api/v1/moderation/comments").ExpectError(t, http.StatusForbidden, "admin privileges required")
+	s.Client().Get(t, "/api/v1/moderation/comments").Expect(t, http.StatusUnauthorized)
+
+	var flagged struct {
+		Comments []domain.Comment `json:"comments"`
+		Total    int64            `json:"total"`
+	}
+	admin.Get(t, "/api/v1/moderation/comments").Expect(t, http.StatusOK).Decode(t, &flagged)
+	if flagged.Total != 2 {
+		t.Fatalf("expected 2 flagged comments, got %d", flagged.Total)
+	}
+
+	admin.Post(t, fmt.Sprintf("/api/v1/moderation/comments/%d/approve", kept.ID), nil).Expect(t, http.StatusOK)
+	admin.Post(t, fmt.Sprintf("/api/v1/moderation/comments/%d/reject", spam.ID), nil).Expect(t, http.StatusOK)
+	admin.Post(t, "/api/v1/moderation/comments/999/approve", nil).ExpectError(t, http.StatusNotFound, "comment not found")
+	admin.Post(t, "/api/v1/moderation/comments/abc/reject", nil).ExpectError(t, http.StatusBadRequest, "invalid comment id")
+
+	admin.Get(t, "/api/v1/moderation/comments").Decode(t, &flagged)
+	if flagged.Total != 0 {
+		t.Fatalf("expected empty moderation queue, got %d", flagged.Total)
+	}
+
+	var list commentList
+	s.Client().Get(t, articlePath(article.ID, "/comments")).Decode(t, &list)
+	if len(list.Comments) != 1 || list.Comments[0].ID != kept.ID {
+		t.Fatalf("rejected comment should be hidden: %+v", list.Comments)
+	}
+	// 被隐藏的评论不能再被举报，作者也不能再修改
+	flagger.Post(t, articlePath(article.ID, fmt.Sprintf("/comments/%d/flag", spam.ID)), nil).ExpectError(t, http.StatusNotFound, "comment not found")
+	c.Put(t, articlePath(article.ID, fmt.Sprintf("/comments/%d", spam.ID)), map[string]string{"content": "Not spam"}).
+		ExpectError(t, http.StatusNotFound, "comment not found")
+
+	// 修改内容不会覆盖审核状态与举报计数
+	s.Login(t, s.CreateUser(t)).Post(t, articlePath(article.ID, fmt.Sprintf("/comments/%d/flag", kept.ID)), nil).Expect(t, http.StatusOK)
+	c.Put(t, articlePath(article.ID, fmt.Sprintf("/comments/%d", kept.ID)), map[string]string{"content": "An edited comment"}).
+		Expect(t, http.StatusOK)
+	var stored domain.Comment
+	if err := s.DB.First(&stored, kept.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Content != "An edited comment" || !stored.Flagged || stored.FlagCount != 2 || stored.Status != domain.CommentStatusVisible {
+		t.Fatalf("unexpected comment after edit: %+v", stored)
+	}
+}
+
+func TestCommentDeleteByContributor(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	owner := s.CreateUser(t)
+	editor := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+	contributorsPath := articlePath(article.ID, "/contributors")
+	c.Post(t, contributorsPath, map[string]any{"user_id": owner.ID, "role": domain.ContributorRoleOwner}).Expect(t, http.StatusOK)
+	c.Post(t, contributorsPath, map[string]any{"user_id": editor.ID, "role": domain.ContributorRoleEditor}).Expect(t, http.StatusOK)
+
+	reader := s.Login(t, s.CreateUser(t))
+	first := createComment(t, reader, article.ID, map[string]any{"content": "First comment"})
+	createComment(t, reader, article.ID, map[string]any{"content": "Second comment"})
+
+	// owner 角色的协作者与文章作者一样可以删除评论，编辑不能
+	firstPath := articlePath(article.ID, fmt.Sprintf("/comments/%d", first.ID))
+	s.Login(t, editor).Delete(t, firstPath).ExpectError(t, http.StatusForbidden, "permission denied")
+	s.Login(t, owner).Delete(t, firstPath).Expect(t, http.StatusOK)
+
+	var got domain.Article
+	s.Client().Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if got.CommentCount != 1 {
+		t.Fatalf("expected comment_count 1, got %d", got.CommentCount)
+	}
+}
@@ -0,0 +1,43 @@
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestConfigValidate(t *testing.T) {
+	if err := testutil.DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config should be valid: %v", err)
+	}
+
+	cases := map[string]func(*config.Config){
+		"views.flushInterval":   func(cfg *config.Config) { cfg.Views.FlushInterval = 0 },
+		"trash.purgeInterval":   func(cfg *config.Config) { cfg.Trash.PurgeInterval = 0 },
+		"privacy.checkInterval": func(cfg *config.Config) { cfg.Privacy.CheckInterval = -1 },
+		"feed.itemCount":        func(cfg *config.Config) { cfg.Feed.ItemCount = 0 },
+	}
+	for key, configure := range cases {
+		cfg := testutil.DefaultConfig()
+		configure(cfg)
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error for %s, got %v", key, err)
+		}
+	}
+
+	cfg := testutil.DefaultConfig()
+	cfg.Feed.ItemCount = 101
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "feed.itemCount") {
+		t.Errorf("expected error for feed.itemCount above the page size limit, got %v", err)
+	}
+
+	// 不自动清理回收站时不启动清理任务，间隔可以不设置
+	cfg = testutil.DefaultConfig()
+	cfg.Trash.RetentionDays = 0
+	cfg.Trash.PurgeInterval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("purge interval should be ignored without retention: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestContributors(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	editor := s.CreateUser(t)
+	viewer := s.CreateUser(t)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c := s.Login(t, author)
+	contributorsPath := articlePath(draft.ID, "/contributors")
+
+	c.Post(t, contributorsPath, map[string]any{"user_id": editor.ID, "role": domain.ContributorRoleEditor}).Expect(t, http.StatusOK)
+	c.Post(t, contributorsPath, map[string]any{"user_id": viewer.ID, "role": domain.ContributorRoleViewer}).Expect(t, http.StatusOK)
+
+	var list struct {
+		Contributors []domain.ArticleContributor `json:"contributors"`
+	}
+	s.Login(t, viewer).Get(t, contributorsPath).Expect(t, http.StatusOK).Decode(t, &list)
+	if len(list.Contributors) != 2 {
+		t.Fatalf("expected 2 contributors, got %+v", list.Contributors)
+	}
+
+	// 协作者可以查看草稿，编辑可以修改，查看者不能
+	s.Login(t, viewer).Get(t, articlePath(draft.ID, "")).Expect(t, http.StatusOK)
+	update := map[string]string{"title": "Edited Together", "content": "Content edited by the editor."}
+	s.Login(t, editor).Put(t, articlePath(draft.ID, ""), update).Expect(t, http.StatusOK)
+	s.Login(t, viewer).Put(t, articlePath(draft.ID, ""), update).ExpectError(t, http.StatusForbidden, "permission denied")
+	// 只有所有者可以删除文章和管理协作者
+	s.Login(t, editor).Delete(t, articlePath(draft.ID, "")).ExpectError(t, http.StatusForbidden, "permission denied")
+	s.Login(t, editor).Post(t, contributorsPath, map[string]any{"user_id": viewer.ID, "role": domain.ContributorRoleEditor}).
+		ExpectError(t, http.StatusForbidden, "permission denied")
+
+	var got domain.Article
+	s.Login(t, viewer).Get(t, articlePath(draft.ID, "")).Decode(t, &got)
+	if len(got.CoAuthors) != 1 || got.CoAuthors[0].UserID != editor.ID {
+		t.Fatalf("expected the editor as co-author, got %+v", got.CoAuthors)
+	}
+
+	shared := listArticles(t, s.Login(t, editor), "/api/v1/users/me/shared-articles")
+	if shared.Total != 1 || shared.Articles[0].ID != draft.ID {
+		t.Fatalf("unexpected shared articles: %+v", shared)
+	}
+
+	// 查看者可以退出协作，所有者可以移除编辑
+	s.Login(t, viewer).Delete(t, fmt.Sprintf("%s/%d", contributorsPath, viewer.ID)).Expect(t, http.StatusOK)
+	s.Login(t, viewer).Get(t, articlePath(draft.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Delete(t, fmt.Sprintf("%s/%d", contributorsPath, editor.ID)).Expect(t, http.StatusOK)
+	if shared := listArticles(t, s.Login(t, editor), "/api/v1/users/me/shared-articles"); shared.Total != 0 {
+		t.Fatalf("expected no shared articles, got %d", shared.Total)
+	}
+}
+
+func TestContributorErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	stranger := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c := s.Login(t, author)
+	contributorsPath := articlePath(article.ID, "/contributors")
+
+	c.Post(t, contributorsPath, map[string]any{"user_id": stranger.ID, "role": "admin"}).Expect(t, http.StatusBadRequest)
+	c.Post(t, contributorsPath, map[string]any{"role": domain.ContributorRoleEditor}).Expect(t, http.StatusBadRequest)
+	c.Post(t, contributorsPath, map[string]any{"user_id": author.ID, "role": domain.ContributorRoleEditor}).
+		ExpectError(t, http.StatusBadRequest, "cannot change the author's role")
+	c.Post(t, contributorsPath, map[string]any{"user_id": 999, "role": domain.ContributorRoleEditor}).
+		ExpectError(t, http.StatusNotFound, "user not found")
+	c.Post(t, "/api/v1/articles/999/contributors", map[string]any{"user_id": stranger.ID, "role": domain.ContributorRoleEditor}).
+		ExpectError(t, http.StatusNotFound, "article not found")
+	c.Post(t, "/api/v1/articles/abc/contributors", map[string]any{"user_id": stranger.ID, "role": domain.ContributorRoleEditor}).
+		ExpectError(t, http.StatusBadRequest, "invalid article id")
+
+	c.Delete(t, fmt.Sprintf("%s/%d", contributorsPath, author.ID)).ExpectError(t, http.StatusBadRequest, "cannot remove the author")
+	c.Delete(t, fmt.Sprintf("%s/%d", contributorsPath, stranger.ID)).ExpectError(t, http.StatusNotFound, "contributor not found")
+	c.Delete(t, contributorsPath+"/abc").ExpectError(t, http.StatusBadRequest, "invalid user id")
+
+	// 没有角色的用户看不到草稿的协作者，已发布文章则无权查看
+	s.Login(t, stranger).Get(t, contributorsPath).ExpectError(t, http.StatusForbidden, "permission denied")
+	s.Login(t, stranger).Get(t, articlePath(draft.ID, "/contributors")).ExpectError(t, http.StatusNotFound, "article not found")
+	s.Client().Get(t, contributorsPath).Expect(t, http.StatusUnauthorized)
+}
@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestLikes(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, s.CreateUser(t))
+
+	c.Post(t, articlePath(article.ID, "/like"), nil).Expect(t, http.StatusOK)
+	// 重复点赞不会重复计数
+	c.Post(t, articlePath(article.ID, "/like"), nil).Expect(t, http.StatusOK)
+
+	var got domain.Article
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if got.LikeCount != 1 || got.LikedByMe == nil || !*got.LikedByMe {
+		t.Fatalf("unexpected like state: count=%d liked=%v", got.LikeCount, got.LikedByMe)
+	}
+
+	c.Delete(t, articlePath(article.ID, "/like")).Expect(t, http.StatusOK)
+	c.Get(t, articlePath(article.ID, "")).Decode(t, &got)
+	if got.LikeCount != 0 || *got.LikedByMe {
+		t.Fatalf("unexpected like state after unlike: count=%d liked=%v", got.LikeCount, *got.LikedByMe)
+	}
+
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c.Post(t, articlePath(draft.ID, "/like"), nil).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Post(t, "/api/v1/articles/999/like", nil).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Post(t, "/api/v1/articles/abc/like", nil).ExpectError(t, http.StatusBadRequest, "invalid article id")
+	s.Client().Post(t, articlePath(article.ID, "/like"), nil).Expect(t, http.StatusUnauthorized)
+}
+
+func TestBookmarks(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, s.CreateUser(t))
+
+	c.Post(t, articlePath(article.ID, "/bookmark"), nil).Expect(t, http.StatusOK)
+	list := listArticles(t, c, "/api/v1/users/me/bookmarks")
+	if list.Total != 1 || list.Articles[0].ID != article.ID {
+		t.Fatalf("unexpected bookmarks: %+v", list)
+	}
+
+	var got domain.Article
+	c.Get(t, articlePath(article.ID, "")).Decode(t, &got)
+	if got.BookmarkedByMe == nil || !*got.BookmarkedByMe {
+		t.Fatal("expected bookmarked_by_me")
+	}
+
+	c.Delete(t, articlePath(article.ID, "/bookmark")).Expect(t, http.StatusOK)
+	if list := listArticles(t, c, "/api/v1/users/me/bookmarks"); list.Total != 0 {
+		t.Fatalf("expected no bookmarks, got %d", list.Total)
+	}
+
+	c.Post(t, "/api/v1/articles/999/bookmark", nil).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Delete(t, "/api/v1/articles/abc/bookmark").ExpectError(t, http.StatusBadRequest, "invalid article id")
+	s.Client().Get(t, "/api/v1/users/me/bookmarks").Expect(t, http.StatusUnauthorized)
+}
+
+func TestViewCount(t *testing.T) {
+	s := testutil.NewServer(t)
+	article := s.CreateArticle(t, s.CreateUser(t))
+	reader := s.Login(t, s.CreateUser(t))
+
+	// 同一访客在去重窗口内只计一次浏览
+	reader.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	reader.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	s.Client().Get(t, "/api/v1/articles/by-slug/"+article.Slug).Expect(t, http.StatusOK)
+	if err := s.Views.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got domain.Article
+	s.Client().Get(t, articlePath(article.ID, "")).Decode(t, &got)
+	if got.ViewCount != 2 {
+		t.Fatalf("expected 2 views, got %d", got.ViewCount)
+	}
+}
@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestProblemResponse(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	header := http.Header{"X-Request-ID": {"req-123"}}
+	problem := c.Do(t, http.MethodGet, "/api/v1/articles/999", nil, header).
+		ExpectCode(t, http.StatusNotFound, "article_not_found")
+	if problem.Type != "about:blank" || problem.Title != "Not Found" || problem.Detail != "article not found" {
+		t.Fatalf("unexpected problem: %+v", problem)
+	}
+	if problem.Instance != "/api/v1/articles/999" || problem.RequestID != "req-123" {
+		t.Fatalf("unexpected problem instance or request id: %+v", problem)
+	}
+
+	c.Get(t, "/api/v1/no-such-route").ExpectCode(t, http.StatusNotFound, "route_not_found")
+}
+
+func TestProblemCodes(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+	other := s.Login(t, s.CreateUser(t))
+
+	update := map[string]string{"title": "Updated title", "content": "Updated article content"}
+	// 更新不存在的文章返回 404 而不是 500
+	c.Put(t, articlePath(999, ""), update).ExpectCode(t, http.StatusNotFound, "article_not_found")
+	other.Put(t, articlePath(article.ID, ""), update).ExpectCode(t, http.StatusForbidden, "permission_denied")
+	c.Put(t, articlePath(article.ID, ""), map[string]string{"title": "x"}).
+		ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	c.Do(t, http.MethodPut, articlePath(article.ID, ""), strings.NewReader("{"), http.Header{"Content-Type": {"application/json"}}).
+		ExpectCode(t, http.StatusBadRequest, "invalid_body")
+	s.Client().Put(t, articlePath(article.ID, ""), update).ExpectCode(t, http.StatusUnauthorized, "missing_token")
+}
+
+func TestInternalErrorHidesDetails(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Login(t, s.CreateUser(t))
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+
+	problem := c.Get(t, "/api/v1/users/me/articles").ExpectCode(t, http.StatusInternalServerError, "internal_error")
+	if problem.Detail != "internal server error" {
+		t.Fatalf("internal error details leaked: %+v", problem)
+	}
+}
+
+func TestGetArticleRepositoryFailure(t *testing.T) {
+	s := testutil.NewServer(t)
+	article := s.CreateArticle(t, s.CreateUser(t))
+	c := s.Client()
+
+	// 系列导航查询失败时返回 500，而不是文章不存在
+	if err := s.DB.Exec("DROP TABLE series_articles").Error; err != nil {
+		t.Fatal(err)
+	}
+	c.Get(t, articlePath(article.ID, "")).ExpectCode(t, http.StatusInternalServerError, "internal_error")
+
+	// 文章查询本身失败时同样返回 500
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+	c.Get(t, articlePath(article.ID, "")).ExpectCode(t, http.StatusInternalServerError, "internal_error")
+	c.Get(t, "/api/v1/articles/by-slug/"+article.Slug).ExpectCode(t, http.StatusInternalServerError, "internal_error")
+}
@@ -0,0 +1,77 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestArticleFeeds(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	s.CreateArticle(t, author, testutil.WithTitle("Published Story"))
+	s.CreateArticle(t, author, testutil.WithTitle("Secret Draft"), testutil.AsDraft())
+	c := s.Client()
+
+	formats := map[string]string{
+		"/feeds/articles.rss":  "application/rss+xml",
+		"/feeds/articles.atom": "application/atom+xml",
+		"/feeds/articles.json": "application/feed+json",
+	}
+	for path, contentType := range formats {
+		resp := c.Get(t, path).Expect(t, http.StatusOK)
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, contentType) {
+			t.Fatalf("%s: unexpected content type %q", path, ct)
+		}
+		body := string(resp.Body)
+		if !strings.Contains(body, "Published Story") || strings.Contains(body, "Secret Draft") {
+			t.Fatalf("%s: unexpected feed body: %s", path, body)
+		}
+
+		// 条件请求命中时返回 304
+		etag := resp.Header.Get("ETag")
+		c.Do(t, http.MethodGet, path, nil, http.Header{"If-None-Match": {etag}}).Expect(t, http.StatusNotModified)
+		lastModified := resp.Header.Get("Last-Modified")
+		c.Do(t, http.MethodGet, path, nil, http.Header{"If-Modified-Since": {lastModified}}).Expect(t, http.StatusNotModified)
+	}
+}
+
+func TestAuthorFeeds(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+	bob := s.CreateUser(t)
+	s.CreateArticle(t, alice, testutil.WithTitle("Alice Writes"))
+	s.CreateArticle(t, alice, testutil.WithTitle("Alice Draft"), testutil.AsDraft())
+	s.CreateArticle(t, bob, testutil.WithTitle("Bob Writes"))
+	c := s.Client()
+
+	for _, ext := range []string{"rss", "atom", "json"} {
+		path := fmt.Sprintf("/feeds/authors/%d/articles.%s", alice.ID, ext)
+		body := string(c.Get(t, path).Expect(t, http.StatusOK).Body)
+		if !strings.Contains(body, "Alice Writes") || strings.Contains(body, "Bob Writes") || strings.Contains(body, "Alice Draft") {
+			t.Fatalf("%s: unexpected feed body: %s", path, body)
+		}
+		if !strings.Contains(body, alice.FullName) {
+			t.Fatalf("%s: expected author name in feed: %s", path, body)
+		}
+
+		c.Get(t, "/feeds/authors/999/articles."+ext).ExpectError(t, http.StatusNotFound, "user not found")
+		c.Get(t, "/feeds/authors/abc/articles."+ext).ExpectError(t, http.StatusBadRequest, "invalid user id")
+	}
+}
+
+func TestFeedBaseURL(t *testing.T) {
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Feed.BaseURL = "https://blog.example.com/"
+	})
+	article := s.CreateArticle(t, s.CreateUser(t))
+
+	body := string(s.Client().Get(t, "/feeds/articles.rss").Expect(t, http.StatusOK).Body)
+	if !strings.Contains(body, fmt.Sprintf("https://blog.example.com/api/v1/articles/%d", article.ID)) {
+		t.Fatalf("expected links to use the configured base url: %s", body)
+	}
+}
@@ -1,23 +1,32 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/search"
 	"github.com/Anning01/user-management/internal/service"
-	"github.com/Anning01/user-management/internal/util"
+	"github.com/Anning01/user-management/pkg/listquery"
+	"github.com/Anning01/user-management/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ArticleHandler struct {
-	articleService service.ArticleService
+	articleService    service.ArticleService
+	engagementService service.EngagementService
 }
 
-func NewArticleHandler(articleService service.ArticleService) *ArticleHandler {
+func NewArticleHandler(articleService service.ArticleService, engagementService service.EngagementService) *ArticleHandler {
 	return &ArticleHandler{
-		articleService: articleService,
+		articleService:    articleService,
+		engagementService: engagementService,
 	}
 }
 
@@ -26,93 +35,474 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	// 从上下文中获取用户ID
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
 	var req struct {
-		Title   string `json:"title" validate:"required,min=3,max=200"`
-		Content string `json:"content" validate:"required,min=10"`
+		Title         string `json:"title" validate:"required,min=3,max=200,nohtml"`
+		Content       string `json:"content" validate:"required,min=10"`
+		ContentFormat string `json:"content_format" validate:"omitempty,oneof=plain markdown"`
+		Slug          string `json:"slug" validate:"omitempty,max=80"`
+		Status        string `json:"status" validate:"omitempty,oneof=draft published"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// 数据验证
-	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
 		return
 	}
 
 	article := &domain.Article{
-		Title:    req.Title,
-		Content:  req.Content,
-		AuthorID: userID.(uint),
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		Slug:          req.Slug,
+		Status:        req.Status,
+		AuthorID:      userID.(uint),
 	}
 
-	if err := h.articleService.CreateArticle(article); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.articleService.CreateArticle(c.Request.Context(), article); err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "article created successfully",
+		"message": message(c, "article_created"),
 		"article": article,
 	})
 }
 
 // GetArticle 获取文章详情
+// 传入 render=html 时额外返回过滤后的 rendered_html 与目录 toc。
 func (h *ArticleHandler) GetArticle(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid article id"})
+		abort(c, errInvalidArticleID)
 		return
 	}
 
-	article, err := h.articleService.GetArticleByID(uint(id))
+	article, err := h.articleService.GetArticleByID(c.Request.Context(), uint(id), viewerID(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, article)
+	h.respondArticle(c, article)
+}
+
+// GetArticleBySlug 通过 slug 获取文章详情，旧 slug 返回 301 重定向到当前地址
+func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
+	article, moved, err := h.articleService.GetArticleBySlug(c.Request.Context(), c.Param("slug"), viewerID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	if moved {
+		location := "/api/v1/articles/by-slug/" + article.Slug
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	h.respondArticle(c, article)
+}
+
+// respondArticle 输出文章详情并记录浏览
+func (h *ArticleHandler) respondArticle(c *gin.Context, article *domain.Article) {
+	if c.Query("render") == "html" {
+		if err := h.articleService.RenderArticle(article); err != nil {
+			abort(c, err)
+			return
+		}
+	}
+
+	h.engagementService.RecordView(article.ID, viewerKey(c))
+
+	articles := []domain.Article{*article}
+	if err := h.annotate(c, articles); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, articles[0])
 }
 
 // ListArticles 获取文章列表
+// 传入 after / before 游标时使用游标分页，否则使用 page / page_size 分页。
+// 支持 sort、filter[...] 和 fields 参数，见 domain.ArticleListSchema。
 func (h *ArticleHandler) ListArticles(c *gin.Context) {
+	q, cursorPage, useCursor, err := parseListQuery(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if useCursor {
+		articles, info, err := h.articleService.ListArticlesByCursor(c.Request.Context(), cursorPage, q)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if err := h.annotate(c, articles); err != nil {
+			abort(c, err)
+			return
+		}
+		items, err := q.Project(articles)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"articles":    items,
+			"next_cursor": info.NextCursor,
+			"prev_cursor": info.PrevCursor,
+			"has_more":    info.HasMore,
+		})
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	articles, total, err := h.articleService.ListArticles(page, pageSize)
+	articles, total, err := h.articleService.ListArticles(c.Request.Context(), page, pageSize, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
+		return
+	}
+	if err := h.annotate(c, articles); err != nil {
+		abort(c, err)
+		return
+	}
+	items, err := q.Project(articles)
+	if err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"articles": articles,
+		"articles":    items,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"next_cursor": nextCursor(articles, q, total, page, pageSize),
+	})
+}
+
+// SearchArticles 全文搜索文章
+func (h *ArticleHandler) SearchArticles(c *gin.Context) {
+	q := search.Query{Text: strings.TrimSpace(c.Query("q"))}
+	if q.Text == "" {
+		abort(c, errMissingQuery)
+		return
+	}
+
+	if v := c.Query("author_id"); v != "" {
+		authorID, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			abort(c, invalidParam("author_id"))
+			return
+		}
+		q.AuthorID = uint(authorID)
+	}
+
+	var err error
+	if q.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		abort(c, invalidParam("created_after"))
+		return
+	}
+	if q.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		abort(c, invalidParam("created_before"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	results, total, err := h.articleService.SearchArticles(c.Request.Context(), q, page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	articles := make([]domain.Article, len(results))
+	for i := range results {
+		articles[i] = results[i].Article
+	}
+	if err := h.annotate(c, articles); err != nil {
+		abort(c, err)
+		return
+	}
+	for i := range results {
+		results[i].Article = articles[i]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":  results,
 		"total":    total,
 		"page":     page,
 		"pageSize": pageSize,
 	})
 }
 
-// ListMyArticles 获取我的文章列表
+// parseTimeQuery 解析时间查询参数，支持 RFC3339 和 2006-01-02 两种格式
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", v, time.Local)
+}
+
+// ListMyArticles 获取我的文章列表（包括草稿，可通过 filter[status] 过滤）
 func (h *ArticleHandler) ListMyArticles(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
+		return
+	}
+
+	q, cursorPage, useCursor, err := parseListQuery(c)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if useCursor {
+		articles, info, err := h.articleService.ListArticlesByAuthorCursor(c.Request.Context(), userID.(uint), true, cursorPage, q)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if err := h.annotate(c, articles); err != nil {
+			abort(c, err)
+			return
+		}
+		items, err := q.Project(articles)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"articles":    items,
+			"next_cursor": info.NextCursor,
+			"prev_cursor": info.PrevCursor,
+			"has_more":    info.HasMore,
+		})
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	articles, total, err := h.articleService.ListArticlesByAuthor(userID.(uint), page, pageSize)
+	articles, total, err := h.articleService.ListArticlesByAuthor(c.Request.Context(), userID.(uint), true, page, pageSize, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
+		return
+	}
+	if err := h.annotate(c, articles); err != nil {
+		abort(c, err)
+		return
+	}
+	items, err := q.Project(articles)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles":    items,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"next_cursor": nextCursor(articles, q, total, page, pageSize),
+	})
+}
+
+// annotate 已登录时为文章填充 liked_by_me / bookmarked_by_me
+func (h *ArticleHandler) annotate(c *gin.Context, articles []domain.Article) error {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return nil
+	}
+	return h.engagementService.AnnotateArticles(c.Request.Context(), userID.(uint), articles)
+}
+
+// viewerID 返回当前登录用户的ID，未登录时返回 0
+func viewerID(c *gin.Context) uint {
+	if userID, exists := c.Get("userID"); exists {
+		return userID.(uint)
+	}
+	return 0
+}
+
+// viewerKey 生成用于浏览量去重的访客标识：登录用户使用用户ID，匿名访客使用 IP 与 User-Agent 的摘要
+func viewerKey(c *gin.Context) string {
+	if userID, exists := c.Get("userID"); exists {
+		return fmt.Sprintf("user:%d", userID.(uint))
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "anon:" + hex.EncodeToString(sum[:8])
+}
+
+// parseListQuery 解析列表接口的排序、过滤、字段选择和游标分页参数
+// 游标分页依赖固定的 (created_at, id) 排序，因此不能与 sort 同时使用。
+func parseListQuery(c *gin.Context) (*listquery.Query, pagination.CursorPage, bool, error) {
+	q, err := listquery.Parse(c.Request.URL.Query(), domain.ArticleListSchema)
+	if err != nil {
+		return nil, pagination.CursorPage{}, false, invalidQuery(err)
+	}
+
+	cursorPage, useCursor, err := parseCursorPage(c)
+	if err != nil {
+		return nil, pagination.CursorPage{}, false, invalidQuery(err)
+	}
+	if useCursor && q.HasSort() {
+		return nil, pagination.CursorPage{}, false, errSortWithCursor
+	}
+
+	return q, cursorPage, useCursor, nil
+}
+
+// parseCursorPage 解析游标分页参数，第二个返回值表示请求是否使用游标分页
+func parseCursorPage(c *gin.Context) (pagination.CursorPage, bool, error) {
+	after, before := c.Query("after"), c.Query("before")
+	if after == "" && before == "" {
+		return pagination.CursorPage{}, false, nil
+	}
+
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	page, err := pagination.NewCursorPage(after, before, pageSize)
+	return page, true, err
+}
+
+// nextCursor 为页码分页的结果生成下一页游标，便于客户端切换到游标分页
+// 游标只对应默认的 (created_at, id) 排序，指定了 sort 时不返回游标。
+func nextCursor(articles []domain.Article, q *listquery.Query, total int64, page, pageSize int) string {
+	if q.HasSort() {
+		return ""
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	if len(articles) == 0 || int64((page-1)*pageSize+len(articles)) >= total {
+		return ""
+	}
+	return service.ArticleCursor(articles[len(articles)-1]).Encode()
+}
+
+// ListFeed 获取我关注的作者发布的文章（游标分页）
+func (h *ArticleHandler) ListFeed(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	cursorPage, err := pagination.NewCursorPage(c.Query("after"), c.Query("before"), pageSize)
+	if err != nil {
+		abort(c, invalidQuery(err))
+		return
+	}
+
+	articles, info, err := h.articleService.ListFeed(c.Request.Context(), userID.(uint), cursorPage)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if err := h.annotate(c, articles); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles":    articles,
+		"next_cursor": info.NextCursor,
+		"prev_cursor": info.PrevCursor,
+		"has_more":    info.HasMore,
+	})
+}
+
+// UpdateArticle 更新文章
+func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	var req struct {
+		Title         string `json:"title" validate:"required,min=3,max=200,nohtml"`
+		Content       string `json:"content" validate:"required,min=10"`
+		ContentFormat string `json:"content_format" validate:"omitempty,oneof=plain markdown"`
+		Slug          string `json:"slug" validate:"omitempty,max=80"`
+		Status        string `json:"status" validate:"omitempty,oneof=draft published"`
+	}
+
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	if err := h.articleService.UpdateArticle(c.Request.Context(), uint(id), userID.(uint), req.Title, req.Content, req.ContentFormat, req.Slug, req.Status); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "article_updated")})
+}
+
+// DeleteArticle 删除文章
+func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	if err := h.articleService.DeleteArticle(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "article_deleted")})
+}
+
+// ListSharedArticles 获取我作为协作者参与的文章
+func (h *ArticleHandler) ListSharedArticles(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	articles, total, err := h.articleService.ListSharedArticles(c.Request.Context(), userID.(uint), page, pageSize)
+	if err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -124,70 +514,87 @@ func (h *ArticleHandler) ListMyArticles(c *gin.Context) {
 	})
 }
 
-// UpdateArticle 更新文章
-func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
+// ListContributors 获取文章的协作者列表（仅协作者可见）
+func (h *ArticleHandler) ListContributors(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid article id"})
+		abort(c, errInvalidArticleID)
 		return
 	}
 
-	var req struct {
-		Title   string `json:"title" validate:"required,min=3,max=200"`
-		Content string `json:"content" validate:"required,min=10"`
+	contributors, err := h.articleService.ListContributors(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		abort(c, err)
+		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"contributors": contributors})
+}
+
+// AddContributor 邀请协作者或修改协作者角色（仅所有者）
+func (h *ArticleHandler) AddContributor(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
 		return
 	}
 
-	// 数据验证
-	if err := util.ValidateStruct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
 		return
 	}
 
-	if err := h.articleService.UpdateArticle(uint(id), userID.(uint), req.Title, req.Content); err != nil {
-		if err.Error() == "permission denied" {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var req struct {
+		UserID uint   `json:"user_id" validate:"required"`
+		Role   string `json:"role" validate:"required,oneof=owner editor viewer"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "article updated successfully"})
+	contributor, err := h.articleService.AddContributor(c.Request.Context(), uint(id), userID.(uint), req.UserID, req.Role)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     message(c, "contributor_saved"),
+		"contributor": contributor,
+	})
 }
 
-// DeleteArticle 删除文章
-func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
+// RemoveContributor 移除协作者，协作者也可以移除自己以退出协作
+func (h *ArticleHandler) RemoveContributor(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid article id"})
+		abort(c, errInvalidArticleID)
+		return
+	}
+	contributorID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidUserID)
 		return
 	}
 
-	if err := h.articleService.DeleteArticle(uint(id), userID.(uint)); err != nil {
-		if err.Error() == "permission denied" {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.articleService.RemoveContributor(c.Request.Context(), uint(id), userID.(uint), uint(contributorID)); err != nil {
+		abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "article deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "contributor_removed")})
 }
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService service.CommentService
+}
+
+func NewCommentHandler(commentService service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// ListComments 获取文章的评论树
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	comments, err := h.commentService.ListComments(c.Request.Context(), uint(articleID))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+// CreateComment 发表评论或回复
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	var req struct {
+		Content  string `json:"content" validate:"required,min=1,max=2000"`
+		ParentID *uint  `json:"parent_id"`
+	}
+
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	comment := &domain.Comment{
+		ArticleID: uint(articleID),
+		AuthorID:  userID.(uint),
+		ParentID:  req.ParentID,
+		Content:   req.Content,
+	}
+
+	if err := h.commentService.CreateComment(c.Request.Context(), comment); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message(c, "comment_created"),
+		"comment": comment,
+	})
+}
+
+// UpdateComment 修改评论（仅评论作者）
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content string `json:"content" validate:"required,min=1,max=2000"`
+	}
+
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), articleID, commentID, userID.(uint), req.Content)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message(c, "comment_updated"),
+		"comment": comment,
+	})
+}
+
+// DeleteComment 删除评论（评论作者、文章作者或管理员）
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), articleID, commentID, userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "comment_deleted")})
+}
+
+// FlagComment 举报评论，被举报的评论进入审核队列
+func (h *CommentHandler) FlagComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" validate:"max=200"`
+	}
+
+	// 举报原因可选，允许空请求体
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abort(c, errInvalidBody.Wrap(err))
+			return
+		}
+	}
+
+	// 数据验证
+	if err := validate(&req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	if err := h.commentService.FlagComment(c.Request.Context(), articleID, commentID, userID.(uint), req.Reason); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "comment_flagged")})
+}
+
+// ListFlaggedComments 获取待审核的评论（管理员）
+func (h *CommentHandler) ListFlaggedComments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	comments, total, err := h.commentService.ListFlaggedComments(c.Request.Context(), page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ApproveComment 审核通过评论（管理员）
+func (h *CommentHandler) ApproveComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidCommentID)
+		return
+	}
+
+	if err := h.commentService.ApproveComment(c.Request.Context(), uint(id)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "comment_approved")})
+}
+
+// RejectComment 审核拒绝并隐藏评论（管理员）
+func (h *CommentHandler) RejectComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidCommentID)
+		return
+	}
+
+	if err := h.commentService.RejectComment(c.Request.Context(), uint(id)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "comment_rejected")})
+}
+
+// parseCommentPath 解析路径中的文章ID和评论ID，失败时已中止请求
+func parseCommentPath(c *gin.Context) (uint, uint, bool) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidCommentID)
+		return 0, 0, false
+	}
+	return uint(articleID), uint(commentID), true
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EngagementHandler struct {
+	engagementService service.EngagementService
+}
+
+func NewEngagementHandler(engagementService service.EngagementService) *EngagementHandler {
+	return &EngagementHandler{
+		engagementService: engagementService,
+	}
+}
+
+// LikeArticle 点赞文章（重复调用不会重复计数）
+func (h *EngagementHandler) LikeArticle(c *gin.Context) {
+	h.handle(c, h.engagementService.LikeArticle, "article_liked")
+}
+
+// UnlikeArticle 取消点赞
+func (h *EngagementHandler) UnlikeArticle(c *gin.Context) {
+	h.handle(c, h.engagementService.UnlikeArticle, "article_unliked")
+}
+
+// BookmarkArticle 收藏文章（重复调用不会重复收藏）
+func (h *EngagementHandler) BookmarkArticle(c *gin.Context) {
+	h.handle(c, h.engagementService.BookmarkArticle, "article_bookmarked")
+}
+
+// UnbookmarkArticle 取消收藏
+func (h *EngagementHandler) UnbookmarkArticle(c *gin.Context) {
+	h.handle(c, h.engagementService.UnbookmarkArticle, "article_unbookmarked")
+}
+
+// ListMyBookmarks 获取我的收藏列表
+func (h *EngagementHandler) ListMyBookmarks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	articles, total, err := h.engagementService.ListBookmarks(c.Request.Context(), userID.(uint), page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles": articles,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// handle 点赞、收藏类接口的通用处理流程
+func (h *EngagementHandler) handle(c *gin.Context, action func(ctx context.Context, userID, articleID uint) error, messageKey string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	if err := action(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, messageKey)})
+}
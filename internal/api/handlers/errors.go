@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Anning01/user-management/internal/api/middleware"
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/domain/errs"
+	"github.com/Anning01/user-management/internal/util"
+	"github.com/Anning01/user-management/pkg/listquery"
+	"github.com/Anning01/user-management/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求参数错误
+var (
+	errUnauthorized     = errs.Unauthorized("unauthorized", "unauthorized")
+	errInvalidBody      = errs.Validation("invalid_body", "invalid request body")
+	errInvalidQuery     = errs.Validation("invalid_query", "invalid query")
+	errInvalidFile      = errs.Validation("invalid_file", "invalid file")
+	errMissingQuery     = errs.Validation("missing_query", "query parameter q is required")
+	errInvalidArticleID = errs.Validation("invalid_article_id", "invalid article id")
+	errInvalidUserID    = errs.Validation("invalid_user_id", "invalid user id")
+	errInvalidCommentID = errs.Validation("invalid_comment_id", "invalid comment id")
+	errInvalidSeriesID  = errs.Validation("invalid_series_id", "invalid series id")
+	errInvalidExportID  = errs.Validation("invalid_export_id", "invalid export id")
+	errInvalidAttachID  = errs.Validation("invalid_attachment_id", "invalid attachment id")
+	errInvalidAuthorID  = errs.Validation("invalid_author_id", "invalid author id")
+	errInvalidSignature = errs.Forbidden("invalid_signature", "invalid or expired signature")
+	errFileNotFound     = errs.NotFound("file_not_found", "file not found")
+	errSortWithCursor   = errs.Validation("sort_with_cursor", "sort cannot be combined with cursor pagination")
+)
+
+// abort 记录错误并中止请求，由 middleware.ErrorHandler 输出错误响应
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// bindJSON 解析 JSON 请求体并校验，失败时返回 Validation 类别的错误
+func bindJSON(c *gin.Context, req interface{}) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+	return validate(req)
+}
+
+// validate 校验请求结构体
+func validate(req interface{}) error {
+	if err := util.ValidateStruct(req); err != nil {
+		return errs.ErrValidation.Wrap(err)
+	}
+	return nil
+}
+
+// queryErrorKeys 查询参数解析错误对应的文案键
+var queryErrorKeys = map[error]string{
+	listquery.ErrUnsupportedSort:   "unsupported_sort_field",
+	listquery.ErrUnsupportedFilter: "unsupported_filter",
+	listquery.ErrInvalidFilter:     "invalid_filter_value",
+	listquery.ErrUnsupportedField:  "unsupported_field",
+	pagination.ErrInvalidCursor:    "invalid_cursor",
+	pagination.ErrCursorConflict:   "cursor_conflict",
+}
+
+// invalidQuery 查询参数解析失败，说明来自解析器
+func invalidQuery(err error) error {
+	var name string
+	var paramErr *listquery.ParamError
+	if errors.As(err, &paramErr) {
+		name = paramErr.Name
+	}
+	for target, key := range queryErrorKeys {
+		if errors.Is(err, target) {
+			return errInvalidQuery.WithMessage(err.Error()).WithKey(key, map[string]string{"name": name})
+		}
+	}
+	return errInvalidQuery.WithMessage(err.Error()).WithKey("invalid_query_detail", map[string]string{"reason": err.Error()})
+}
+
+// invalidParam 查询参数格式错误
+func invalidParam(name string) error {
+	return errInvalidQuery.WithMessage("invalid "+name).WithKey("invalid_param", map[string]string{"name": name})
+}
+
+// message 按当前请求的语言返回成功提示，key 为消息目录中 message. 之后的部分
+func message(c *gin.Context, key string) string {
+	return middleware.Localizer(c).T("message."+key, nil)
+}
+
+// formFileError 读取上传文件失败时的错误
+func formFileError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return domain.ErrFileTooLarge.Wrap(err)
+	}
+	return errInvalidFile.Wrap(err)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+	"github.com/Anning01/user-management/pkg/feed"
+
+	"github.com/gin-gonic/gin"
+)
+
+// summaryLength 订阅源摘要的最大字符数
+const summaryLength = 200
+
+type feedFormat struct {
+	name        string
+	contentType string
+	render      func(*feed.Feed) ([]byte, error)
+}
+
+var (
+	formatRSS  = feedFormat{"rss", feed.ContentTypeRSS, (*feed.Feed).RSS}
+	formatAtom = feedFormat{"atom", feed.ContentTypeAtom, (*feed.Feed).Atom}
+	formatJSON = feedFormat{"json", feed.ContentTypeJSON, (*feed.Feed).JSON}
+)
+
+type FeedHandler struct {
+	articleService service.ArticleService
+	userService    service.UserService
+	feedConfig     *config.FeedConfig
+}
+
+func NewFeedHandler(articleService service.ArticleService, userService service.UserService, feedConfig *config.FeedConfig) *FeedHandler {
+	return &FeedHandler{
+		articleService: articleService,
+		userService:    userService,
+		feedConfig:     feedConfig,
+	}
+}
+
+// ArticlesRSS 全站文章 RSS 2.0 订阅源
+func (h *FeedHandler) ArticlesRSS(c *gin.Context) { h.serveArticles(c, formatRSS) }
+
+// ArticlesAtom 全站文章 Atom 订阅源
+func (h *FeedHandler) ArticlesAtom(c *gin.Context) { h.serveArticles(c, formatAtom) }
+
+// ArticlesJSON 全站文章 JSON Feed 订阅源
+func (h *FeedHandler) ArticlesJSON(c *gin.Context) { h.serveArticles(c, formatJSON) }
+
+// AuthorRSS 指定作者的 RSS 2.0 订阅源
+func (h *FeedHandler) AuthorRSS(c *gin.Context) { h.serveAuthor(c, formatRSS) }
+
+// AuthorAtom 指定作者的 Atom 订阅源
+func (h *FeedHandler) AuthorAtom(c *gin.Context) { h.serveAuthor(c, formatAtom) }
+
+// AuthorJSON 指定作者的 JSON Feed 订阅源
+func (h *FeedHandler) AuthorJSON(c *gin.Context) { h.serveAuthor(c, formatJSON) }
+
+func (h *FeedHandler) serveArticles(c *gin.Context, format feedFormat) {
+	articles, _, err := h.articleService.ListArticles(c.Request.Context(), 1, h.feedConfig.ItemCount, nil)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	base := h.baseURL(c)
+	f := &feed.Feed{
+		Title:       h.feedConfig.Title,
+		Description: h.feedConfig.Description,
+		Link:        base + "/api/v1/articles",
+		FeedURL:     base + c.Request.URL.Path,
+	}
+	h.render(c, format, f, articles)
+}
+
+func (h *FeedHandler) serveAuthor(c *gin.Context, format feedFormat) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	author, err := h.userService.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		abort(c, domain.ErrUserNotFound)
+		return
+	}
+
+	articles, _, err := h.articleService.ListArticlesByAuthor(c.Request.Context(), id, false, 1, h.feedConfig.ItemCount, nil)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	for i := range articles {
+		articles[i].Author = *author
+	}
+
+	base := h.baseURL(c)
+	f := &feed.Feed{
+		Title:       fmt.Sprintf("%s - %s", h.feedConfig.Title, displayName(author)),
+		Description: fmt.Sprintf("Articles by %s", displayName(author)),
+		Link:        fmt.Sprintf("%s/api/v1/users/%d", base, id),
+		FeedURL:     base + c.Request.URL.Path,
+	}
+	h.render(c, format, f, articles)
+}
+
+// render 填充订阅条目并输出，支持 ETag / Last-Modified 条件请求
+func (h *FeedHandler) render(c *gin.Context, format feedFormat, f *feed.Feed, articles []domain.Article) {
+	base := h.baseURL(c)
+	digest := sha256.New()
+	fmt.Fprintf(digest, "%s|%s|", format.name, f.FeedURL)
+
+	for _, article := range articles {
+		if err := h.articleService.RenderArticle(&article); err != nil {
+			abort(c, err)
+			return
+		}
+		summary := article.Excerpt
+		if summary == "" {
+			summary = summarize(article.Content)
+		}
+
+		link := fmt.Sprintf("%s/api/v1/articles/%d", base, article.ID)
+		f.Items = append(f.Items, feed.Item{
+			ID:          link,
+			Title:       article.Title,
+			Link:        link,
+			Summary:     summary,
+			Content:     article.Content,
+			ContentHTML: article.RenderedHTML,
+			Author:      displayName(&article.Author),
+			Published:   article.CreatedAt,
+			Updated:     article.UpdatedAt,
+		})
+		if article.UpdatedAt.After(f.Updated) {
+			f.Updated = article.UpdatedAt
+		}
+		fmt.Fprintf(digest, "%d:%d|", article.ID, article.UpdatedAt.UnixNano())
+	}
+
+	etag := `W/"` + hex.EncodeToString(digest.Sum(nil)[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !f.Updated.IsZero() {
+		c.Header("Last-Modified", f.Updated.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, f.Updated) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, err := format.render(f)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.Data(http.StatusOK, format.contentType, body)
+}
+
+// notModified 判断客户端缓存是否仍然有效（If-None-Match 优先于 If-Modified-Since）
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			if tag = strings.TrimSpace(tag); tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// baseURL 订阅源中链接使用的站点地址，未配置时根据请求推断
+func (h *FeedHandler) baseURL(c *gin.Context) string {
+	if h.feedConfig.BaseURL != "" {
+		return strings.TrimRight(h.feedConfig.BaseURL, "/")
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// summarize 截取正文开头作为摘要
+func summarize(content string) string {
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) <= summaryLength {
+		return string(runes)
+	}
+	return string(runes[:summaryLength]) + "…"
+}
+
+func displayName(user *domain.User) string {
+	if user.FullName != "" {
+		return user.FullName
+	}
+	return user.Username
+}
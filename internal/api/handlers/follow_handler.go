@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followService service.FollowService
+	userService   service.UserService
+}
+
+func NewFollowHandler(followService service.FollowService, userService service.UserService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+		userService:   userService,
+	}
+}
+
+// GetUserProfile 获取用户公开资料及关注数
+func (h *FollowHandler) GetUserProfile(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		abort(c, domain.ErrUserNotFound)
+		return
+	}
+
+	followers, following, err := h.followService.GetFollowCounts(c.Request.Context(), id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	profile := publicUser(user)
+	profile["followers_count"] = followers
+	profile["following_count"] = following
+
+	// 已登录时返回当前用户是否已关注该用户
+	if currentUserID, exists := c.Get("userID"); exists {
+		followed, err := h.followService.IsFollowing(c.Request.Context(), currentUserID.(uint), id)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		profile["followed_by_me"] = followed
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Follow 关注用户（重复调用不会重复关注）
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.followService.Follow(c.Request.Context(), userID.(uint), id); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "user_followed")})
+}
+
+// Unfollow 取消关注
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.followService.Unfollow(c.Request.Context(), userID.(uint), id); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "user_unfollowed")})
+}
+
+// ListFollowers 获取用户的粉丝列表
+func (h *FollowHandler) ListFollowers(c *gin.Context) {
+	h.listUsers(c, h.followService.ListFollowers)
+}
+
+// ListFollowing 获取用户关注的人
+func (h *FollowHandler) ListFollowing(c *gin.Context) {
+	h.listUsers(c, h.followService.ListFollowing)
+}
+
+func (h *FollowHandler) listUsers(c *gin.Context, list func(ctx context.Context, userID uint, page, pageSize int) ([]domain.User, int64, error)) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	users, total, err := list(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(users))
+	for i := range users {
+		items = append(items, publicUser(&users[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":    items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// parseUserID 解析路径中的用户ID，失败时已中止请求
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidUserID)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// publicUser 用户的公开信息（不包含邮箱等隐私字段）
+func publicUser(user *domain.User) gin.H {
+	return withAvatar(gin.H{
+		"id":        user.ID,
+		"username":  user.Username,
+		"full_name": user.FullName,
+	}, user)
+}
+
+// withAvatar 用户设置了头像时附加头像地址，地址会重定向到带签名的下载链接
+func withAvatar(fields gin.H, user *domain.User) gin.H {
+	if user.AvatarKey != "" {
+		fields["avatar_url"] = fmt.Sprintf("/api/v1/users/%d/avatar", user.ID)
+		fields["avatar_thumb_url"] = fmt.Sprintf("/api/v1/users/%d/avatar?size=thumb", user.ID)
+	}
+	return fields
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// RequestExport 申请导出个人数据，压缩包在后台生成
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	export, err := h.privacyService.RequestExport(c.Request.Context(), userID.(uint))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": message(c, "export_requested"),
+		"export":  export,
+	})
+}
+
+// ListExports 我的数据导出，已完成的导出包含下载地址
+func (h *PrivacyHandler) ListExports(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	exports, err := h.privacyService.ListExports(c.Request.Context(), userID.(uint))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exports": exports})
+}
+
+// GetExport 查询导出状态
+func (h *PrivacyHandler) GetExport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidExportID)
+		return
+	}
+
+	export, err := h.privacyService.GetExport(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// RequestErasure 申请注销账号，冷静期结束后匿名化或删除账号数据
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		Password     string `json:"password" validate:"required"`
+		KeepArticles bool   `json:"keep_articles"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	request, err := h.privacyService.RequestErasure(c.Request.Context(), userID.(uint), req.Password, req.KeepArticles)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": message(c, "erasure_scheduled"),
+		"erasure": request,
+	})
+}
+
+// GetErasure 查询待执行的注销请求
+func (h *PrivacyHandler) GetErasure(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	request, err := h.privacyService.GetErasure(c.Request.Context(), userID.(uint))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// CancelErasure 在冷静期内撤销注销请求
+func (h *PrivacyHandler) CancelErasure(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	if err := h.privacyService.CancelErasure(c.Request.Context(), userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "erasure_cancelled")})
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	seriesService service.SeriesService
+}
+
+func NewSeriesHandler(seriesService service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{seriesService: seriesService}
+}
+
+// CreateSeries 创建系列
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title" validate:"required,min=3,max=200,nohtml"`
+		Description string `json:"description" validate:"max=1000"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	series := &domain.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		AuthorID:    userID.(uint),
+	}
+	if err := h.seriesService.CreateSeries(c.Request.Context(), series); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message(c, "series_created"),
+		"series":  series,
+	})
+}
+
+// GetSeries 获取系列详情及按顺序排列的文章
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(c.Request.Context(), id, viewerID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// ListUserSeries 获取用户创建的系列
+func (h *SeriesHandler) ListUserSeries(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	series, total, err := h.seriesService.ListSeriesByAuthor(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series":   series,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// UpdateSeries 更新系列标题与简介（仅系列作者）
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title" validate:"required,min=3,max=200,nohtml"`
+		Description string `json:"description" validate:"max=1000"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	if err := h.seriesService.UpdateSeries(c.Request.Context(), id, userID.(uint), req.Title, req.Description); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "series_updated")})
+}
+
+// DeleteSeries 删除系列，其中的文章保留（仅系列作者）
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.seriesService.DeleteSeries(c.Request.Context(), id, userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "series_deleted")})
+}
+
+// AddArticle 将文章追加到系列末尾（仅系列作者，且需要能编辑该文章）
+func (h *SeriesHandler) AddArticle(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ArticleID uint `json:"article_id" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	entry, err := h.seriesService.AddArticle(c.Request.Context(), id, userID.(uint), req.ArticleID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message(c, "series_article_added"),
+		"entry":   entry,
+	})
+}
+
+// ReorderArticles 按 article_ids 的顺序重新排列系列中的全部文章（仅系列作者）
+func (h *SeriesHandler) ReorderArticles(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ArticleIDs []uint `json:"article_ids" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
+		return
+	}
+
+	if err := h.seriesService.ReorderArticles(c.Request.Context(), id, userID.(uint), req.ArticleIDs); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "series_reordered")})
+}
+
+// RemoveArticle 从系列中移除文章，文章本身保留（仅系列作者）
+func (h *SeriesHandler) RemoveArticle(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, ok := parseSeriesID(c)
+	if !ok {
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("article_id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	if err := h.seriesService.RemoveArticle(c.Request.Context(), id, userID.(uint), uint(articleID)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "series_article_removed")})
+}
+
+// parseSeriesID 解析路径中的系列ID，失败时已中止请求
+func parseSeriesID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidSeriesID)
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Anning01/user-management/internal/api/middleware"
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+	"github.com/Anning01/user-management/internal/util"
+	"github.com/Anning01/user-management/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	transferService service.TransferService
+	maxImportSize   int64
+}
+
+func NewTransferHandler(transferService service.TransferService, maxImportSize int64) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+		maxImportSize:   maxImportSize,
+	}
+}
+
+// ExportMyArticles 导出我的文章（format=jsonl 或 markdown）
+func (h *TransferHandler) ExportMyArticles(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+	h.export(c, userID.(uint))
+}
+
+// ExportArticles 导出全部文章，可通过 author_id 指定作者（管理员）
+func (h *TransferHandler) ExportArticles(c *gin.Context) {
+	var authorID uint
+	if v := c.Query("author_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			abort(c, errInvalidAuthorID)
+			return
+		}
+		authorID = uint(id)
+	}
+	h.export(c, authorID)
+}
+
+func (h *TransferHandler) export(c *gin.Context, authorID uint) {
+	format := c.DefaultQuery("format", domain.TransferFormatJSONL)
+	var contentType, fileName string
+	switch format {
+	case domain.TransferFormatJSONL:
+		contentType, fileName = "application/x-ndjson", "articles.jsonl"
+	case domain.TransferFormatMarkdown:
+		contentType, fileName = "application/zip", "articles.zip"
+	default:
+		abort(c, domain.ErrUnsupportedTransferFormat)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Status(http.StatusOK)
+
+	// 响应头已发送，导出中途出错只能记录日志
+	if _, err := h.transferService.ExportArticles(c.Request.Context(), c.Writer, format, authorID); err != nil {
+		logger.Error(c.Request.Context(), "failed to export articles", logger.Err(err))
+	}
+}
+
+// ImportMyArticles 导入文章到我的名下（multipart 表单字段 file，dry_run=true 时只校验）
+func (h *TransferHandler) ImportMyArticles(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+	h.importArticles(c, service.ImportOptions{AuthorID: userID.(uint)})
+}
+
+// ImportArticles 导入文章，记录可通过 author_id 指定作者，未指定时归属当前管理员（管理员）
+func (h *TransferHandler) ImportArticles(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+	h.importArticles(c, service.ImportOptions{AuthorID: userID.(uint), AllowAuthor: true})
+}
+
+func (h *TransferHandler) importArticles(c *gin.Context, opts service.ImportOptions) {
+	opts.DryRun, _ = strconv.ParseBool(c.Query("dry_run"))
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxImportSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		abort(c, formFileError(err))
+		return
+	}
+	if header.Size > h.maxImportSize {
+		abort(c, domain.ErrFileTooLarge)
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = service.TransferFormatFromFileName(header.Filename)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		abort(c, errInvalidFile.Wrap(err))
+		return
+	}
+	defer file.Close()
+
+	report, err := h.transferService.ImportArticles(c.Request.Context(), file, header.Size, format, opts)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	localizeImportErrors(c, report)
+	c.JSON(http.StatusOK, report)
+}
+
+// localizeImportErrors 按请求语言翻译失败行的错误说明，校验失败时列出各字段的错误；
+// 文件解析错误等非领域错误保留原始说明，便于定位文件内容
+func localizeImportErrors(c *gin.Context, report *domain.ImportReport) {
+	locale := middleware.Localizer(c).Locale()
+	for i := range report.Errors {
+		row := &report.Errors[i]
+		if fields := util.FieldErrors(row.Err, locale); len(fields) > 0 {
+			messages := make([]string, len(fields))
+			for j, field := range fields {
+				messages[j] = field.Message
+			}
+			row.Error = strings.Join(messages, "; ")
+		} else if row.Code != "" {
+			row.Error = middleware.ErrorMessage(c, row.Err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService service.TrashService
+	retention    time.Duration
+}
+
+// NewTrashHandler retention 为回收站保留期，用于计算 purge_at；为 0 表示不会自动清理
+func NewTrashHandler(trashService service.TrashService, retention time.Duration) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+		retention:    retention,
+	}
+}
+
+// ListMyTrash 获取我删除的文章
+func (h *TrashHandler) ListMyTrash(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+	h.listArticles(c, userID.(uint))
+}
+
+// ListDeletedArticles 获取回收站中的全部文章（管理员）
+func (h *TrashHandler) ListDeletedArticles(c *gin.Context) {
+	h.listArticles(c, 0)
+}
+
+func (h *TrashHandler) listArticles(c *gin.Context, authorID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	articles, total, err := h.trashService.ListDeletedArticles(c.Request.Context(), authorID, page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(articles))
+	for _, article := range articles {
+		items = append(items, h.trashItem("article", article, article.DeletedAt.Time))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles": items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ListDeletedUsers 获取回收站中的用户（管理员）
+func (h *TrashHandler) ListDeletedUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	users, total, err := h.trashService.ListDeletedUsers(c.Request.Context(), page, pageSize)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(users))
+	for _, user := range users {
+		items = append(items, h.trashItem("user", user, user.DeletedAt.Time))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":    items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// RestoreArticle 从回收站恢复文章（作者或管理员）
+func (h *TrashHandler) RestoreArticle(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	if err := h.trashService.RestoreArticle(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "article_restored")})
+}
+
+// RestoreUser 从回收站恢复用户及随其删除的文章（管理员）
+func (h *TrashHandler) RestoreUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreUser(c.Request.Context(), id); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "user_restored")})
+}
+
+// trashItem 回收站条目，附带删除时间和预计彻底删除的时间
+func (h *TrashHandler) trashItem(name string, value interface{}, deletedAt time.Time) gin.H {
+	item := gin.H{
+		name:         value,
+		"deleted_at": deletedAt,
+	}
+	if h.retention > 0 {
+		item["purge_at"] = deletedAt.Add(h.retention)
+	}
+	return item
+}
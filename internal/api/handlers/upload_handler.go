@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Anning01/user-management/internal/service"
+	"github.com/Anning01/user-management/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead 限制请求体大小时为 multipart 边界和表单字段预留的字节数
+const multipartOverhead = 1 << 20
+
+type UploadHandler struct {
+	attachmentService service.AttachmentService
+	avatarService     service.AvatarService
+	store             storage.Storage
+	signer            *storage.Signer
+	maxSize           int64
+	avatarMaxSize     int64
+}
+
+func NewUploadHandler(attachmentService service.AttachmentService, avatarService service.AvatarService, store storage.Storage, signer *storage.Signer, maxSize, avatarMaxSize int64) *UploadHandler {
+	return &UploadHandler{
+		attachmentService: attachmentService,
+		avatarService:     avatarService,
+		store:             store,
+		signer:            signer,
+		maxSize:           maxSize,
+		avatarMaxSize:     avatarMaxSize,
+	}
+}
+
+// UploadAttachment 上传文章附件（multipart 表单字段 file）
+func (h *UploadHandler) UploadAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		abort(c, formFileError(err))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		abort(c, errInvalidFile.Wrap(err))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), uint(articleID), userID.(uint), header.Filename, header.Size, file)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    message(c, "attachment_uploaded"),
+		"attachment": attachment,
+	})
+}
+
+// ListAttachments 获取文章附件列表
+func (h *UploadHandler) ListAttachments(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+
+	attachments, err := h.attachmentService.ListByArticle(c.Request.Context(), uint(articleID), viewerID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// DeleteAttachment 删除文章附件
+func (h *UploadHandler) DeleteAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidArticleID)
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		abort(c, errInvalidAttachID)
+		return
+	}
+
+	if err := h.attachmentService.Delete(c.Request.Context(), uint(articleID), uint(attachmentID), userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "attachment_deleted")})
+}
+
+// UploadAvatar 上传当前用户的头像（multipart 表单字段 file）
+func (h *UploadHandler) UploadAvatar(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.avatarMaxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		abort(c, formFileError(err))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		abort(c, errInvalidFile.Wrap(err))
+		return
+	}
+	defer file.Close()
+
+	user, err := h.avatarService.Upload(c.Request.Context(), userID.(uint), header.Size, file)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message(c, "avatar_uploaded"),
+		"user":    publicUser(user),
+	})
+}
+
+// DeleteAvatar 删除当前用户的头像
+func (h *UploadHandler) DeleteAvatar(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		abort(c, errUnauthorized)
+		return
+	}
+
+	if err := h.avatarService.Delete(c.Request.Context(), userID.(uint)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "avatar_deleted")})
+}
+
+// GetAvatar 重定向到用户头像的签名下载地址，size=thumb 时返回缩略图
+func (h *UploadHandler) GetAvatar(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.avatarService.URL(c.Request.Context(), id, c.Query("size") == "thumb")
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	// 签名地址会过期，重定向结果不应被长期缓存
+	c.Header("Cache-Control", "private, max-age=60")
+	c.Redirect(http.StatusFound, url)
+}
+
+// ServeFile 提供本地存储文件的下载，需要有效的签名
+func (h *UploadHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.signer.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		abort(c, errInvalidSignature.Wrap(err))
+		return
+	}
+
+	reader, info, err := h.store.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			abort(c, errFileNotFound)
+			return
+		}
+		abort(c, err)
+		return
+	}
+	defer reader.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	headers := map[string]string{
+		"Cache-Control":          "private, max-age=3600",
+		"X-Content-Type-Options": "nosniff",
+	}
+	// 非图片文件一律作为下载处理，避免在站点域名下直接渲染
+	if !strings.HasPrefix(contentType, "image/") {
+		headers["Content-Disposition"] = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, info.Size, contentType, reader, headers)
+}
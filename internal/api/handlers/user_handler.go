@@ -3,9 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Anning01/user-management/internal/api/middleware"
 	"github.com/Anning01/user-management/internal/domain"
 	"github.com/Anning01/user-management/internal/service"
-	"github.com/Anning01/user-management/internal/util"
 	"github.com/Anning01/user-management/pkg/security"
 
 	"github.com/Anning01/user-management/internal/config"
@@ -13,38 +13,57 @@ import (
 )
 
 type UserHandler struct {
-	userService service.UserService
-	jwtConfig   *config.JWTConfig
+	userService   service.UserService
+	followService service.FollowService
+	jwtConfig     *config.JWTConfig
 }
 
-func NewUserHandler(userService service.UserService, jwtConfig *config.JWTConfig) *UserHandler {
+func NewUserHandler(userService service.UserService, followService service.FollowService, jwtConfig *config.JWTConfig) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		jwtConfig:   jwtConfig,
+		userService:   userService,
+		followService: followService,
+		jwtConfig:     jwtConfig,
 	}
 }
 
+// registerRequest 注册请求，User.Password 不参与 JSON 序列化，因此单独定义
+type registerRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50,username"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+	FullName string `json:"full_name" validate:"max=100"`
+	Locale   string `json:"locale" validate:"max=20"`
+}
+
 // Register 用户注册
 func (h *UserHandler) Register(c *gin.Context) {
-	var user domain.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req registerRequest
+	if err := bindJSON(c, &req); err != nil {
+		abort(c, err)
 		return
 	}
 
-	// 数据验证
-	if err := util.ValidateStruct(user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	user := domain.User{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
 	}
-
-	if err := h.userService.Register(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if req.Locale != "" {
+		locale, err := supportedLocale(c, req.Locale)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		user.Locale = locale
+	}
+	if err := h.userService.Register(c.Request.Context(), &user); err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "user registered successfully",
+		"message": message(c, "user_registered"),
 		"user": gin.H{
 			"id":       user.ID,
 			"username": user.Username,
@@ -60,32 +79,26 @@ func (h *UserHandler) Login(c *gin.Context) {
 		Password string `json:"password" validate:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&loginData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &loginData); err != nil {
+		abort(c, err)
 		return
 	}
 
-	// 数据验证
-	if err := util.ValidateStruct(loginData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	user, err := h.userService.Login(loginData.Email, loginData.Password)
+	user, err := h.userService.Login(c.Request.Context(), loginData.Email, loginData.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	// 生成JWT令牌
 	token, err := security.GenerateToken(user.ID, h.jwtConfig.SecretKey, h.jwtConfig.ExpirationHours)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "login successful",
+		"message": message(c, "login_successful"),
 		"token":   token,
 		"user": gin.H{
 			"id":       user.ID,
@@ -99,51 +112,56 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
-	user, err := h.userService.GetUserByID(userID.(uint))
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		abort(c, domain.ErrUserNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":        user.ID,
-		"username":  user.Username,
-		"email":     user.Email,
-		"full_name": user.FullName,
-	})
+	followers, following, err := h.followService.GetFollowCounts(c.Request.Context(), user.ID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withAvatar(gin.H{
+		"id":              user.ID,
+		"username":        user.Username,
+		"email":           user.Email,
+		"full_name":       user.FullName,
+		"locale":          user.Locale,
+		"followers_count": followers,
+		"following_count": following,
+	}, user))
 }
 
 // UpdateCurrentUser 更新当前用户信息
 func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
 	var updateData struct {
 		FullName string `json:"full_name"`
 		Email    string `json:"email" validate:"omitempty,email"`
+		// Locale 为空字符串时清除语言偏好，不传时保持不变
+		Locale *string `json:"locale" validate:"omitempty,max=20"`
 	}
 
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// 数据验证
-	if err := util.ValidateStruct(updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &updateData); err != nil {
+		abort(c, err)
 		return
 	}
 
-	user, err := h.userService.GetUserByID(userID.(uint))
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		abort(c, domain.ErrUserNotFound)
 		return
 	}
 
@@ -154,35 +172,54 @@ func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
 	if updateData.Email != "" {
 		user.Email = updateData.Email
 	}
+	if updateData.Locale != nil {
+		user.Locale = ""
+		if *updateData.Locale != "" {
+			if user.Locale, err = supportedLocale(c, *updateData.Locale); err != nil {
+				abort(c, err)
+				return
+			}
+		}
+	}
 
-	if err := h.userService.UpdateUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.UpdateUser(c.Request.Context(), user); err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "user updated successfully",
+		"message": message(c, "user_updated"),
 		"user": gin.H{
 			"id":        user.ID,
 			"username":  user.Username,
 			"email":     user.Email,
 			"full_name": user.FullName,
+			"locale":    user.Locale,
 		},
 	})
 }
 
+// supportedLocale 校验语言偏好是否有对应的消息目录，返回目录中的写法，例如 zh-cn 返回 zh-CN
+func supportedLocale(c *gin.Context, locale string) (string, error) {
+	canonical, ok := middleware.Bundle(c).Canonical(locale)
+	if !ok {
+		return "", domain.ErrUnsupportedLocale
+	}
+	return canonical, nil
+}
+
 // DeleteCurrentUser 删除当前用户（注销账号）
 func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		abort(c, errUnauthorized)
 		return
 	}
 
-	if err := h.userService.DeleteUser(userID.(uint)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.DeleteUser(c.Request.Context(), userID.(uint)); err != nil {
+		abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": message(c, "user_deleted")})
 }
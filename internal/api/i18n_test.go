@@ -0,0 +1,134 @@
+package api_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestLocalizedMessages(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	c := s.Login(t, author)
+	c.Header = http.Header{"Accept-Language": {"zh-CN,zh;q=0.9,en;q=0.8"}}
+
+	resp := c.Post(t, "/api/v1/articles", map[string]string{"title": "Hello", "content": "Article content here"}).
+		Expect(t, http.StatusCreated).JSON(t)
+	if resp["message"] != "文章创建成功" {
+		t.Fatalf("expected chinese message, got %v", resp["message"])
+	}
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "文章不存在")
+	c.Get(t, "/api/v1/articles?sort=password").ExpectError(t, http.StatusBadRequest, "不支持按 password 排序")
+	c.Get(t, "/api/v1/articles/search?q=go&author_id=abc").ExpectError(t, http.StatusBadRequest, "参数 author_id 无效")
+
+	anonymous := s.Client()
+	anonymous.Header = http.Header{"Accept-Language": {"zh-TW"}}
+	anonymous.Get(t, "/api/v1/users/me").ExpectError(t, http.StatusUnauthorized, "缺少 Authorization 请求头")
+	anonymous.Header = http.Header{"Accept-Language": {"fr-FR, de;q=0.5"}}
+	anonymous.Get(t, "/api/v1/users/me").ExpectError(t, http.StatusUnauthorized, "authorization header is required")
+
+	// 错误码与语言无关
+	c.Get(t, "/api/v1/articles/999").ExpectCode(t, http.StatusNotFound, "article_not_found")
+}
+
+func TestUserLocalePreference(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Login(t, s.CreateUser(t))
+	c.Header = http.Header{"Accept-Language": {"en"}}
+
+	resp := c.Put(t, "/api/v1/users/me", map[string]string{"locale": "zh-cn"}).Expect(t, http.StatusOK).JSON(t)
+	if resp["message"] != "用户信息已更新" || resp["user"].(map[string]any)["locale"] != "zh-CN" {
+		t.Fatalf("unexpected update response: %v", resp)
+	}
+	// 语言偏好优先于 Accept-Language
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "文章不存在")
+	if me := c.Get(t, "/api/v1/users/me").Expect(t, http.StatusOK).JSON(t); me["locale"] != "zh-CN" {
+		t.Fatalf("expected locale zh-CN, got %v", me["locale"])
+	}
+
+	c.Put(t, "/api/v1/users/me", map[string]string{"locale": "fr"}).
+		ExpectCode(t, http.StatusBadRequest, "unsupported_locale")
+
+	// 清除偏好后恢复按 Accept-Language 协商
+	c.Put(t, "/api/v1/users/me", map[string]string{"locale": ""}).Expect(t, http.StatusOK)
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "article not found")
+
+	// 注册时可以指定语言偏好
+	var registered struct {
+		User struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	c.Post(t, "/api/v1/users/register", map[string]string{
+		"username": "bob", "email": "bob@example.com", "password": "secret123", "locale": "zh-CN",
+	}).Expect(t, http.StatusCreated).Decode(t, &registered)
+	bob, err := s.Users.GetUserByID(t.Context(), registered.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob.Locale != "zh-CN" {
+		t.Fatalf("expected locale zh-CN, got %q", bob.Locale)
+	}
+}
+
+func TestCustomMessageCatalog(t *testing.T) {
+	dir := t.TempDir()
+	catalogs := map[string]string{
+		"ja.json": `{"message.article_created": "記事を作成しました"}`,
+		"en.json": `{"error.article_not_found": "no such article"}`,
+	}
+	for name, content := range catalogs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.I18n.Dir = dir
+		cfg.I18n.DefaultLocale = "zh-CN"
+	})
+	c := s.Login(t, s.CreateUser(t))
+
+	// 未指定语言时使用默认语言
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "文章不存在")
+
+	c.Header = http.Header{"Accept-Language": {"ja-JP"}}
+	resp := c.Post(t, "/api/v1/articles", map[string]string{"title": "Hello", "content": "Article content here"}).
+		Expect(t, http.StatusCreated).JSON(t)
+	if resp["message"] != "記事を作成しました" {
+		t.Fatalf("expected japanese message, got %v", resp["message"])
+	}
+	// 新语言缺少的文案回退到默认语言
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "文章不存在")
+
+	c.Header = http.Header{"Accept-Language": {"en"}}
+	c.Get(t, "/api/v1/articles/999").ExpectError(t, http.StatusNotFound, "no such article")
+}
+
+func TestLocalizedImportErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+	admin.Header = http.Header{"Accept-Language": {"zh-CN"}}
+
+	file := jsonLines(t,
+		domain.ArticleRecord{ExternalID: "post-1", Title: "No", Content: "Title is too short.", AuthorID: 1},
+		domain.ArticleRecord{ExternalID: "post-2", Title: "Unknown Author", Content: "The author does not exist.", AuthorID: 999},
+	)
+	var report domain.ImportReport
+	admin.Upload(t, http.MethodPost, "/api/v1/articles/import", "file", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusOK).Decode(t, &report)
+	if len(report.Errors) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if e := report.Errors[0]; e.Code != "validation_failed" || e.Error != "title长度必须至少为3个字符" {
+		t.Fatalf("unexpected validation error: %+v", e)
+	}
+	if e := report.Errors[1]; e.Code != "author_not_found" || e.Error != "作者不存在" {
+		t.Fatalf("unexpected author error: %+v", e)
+	}
+}
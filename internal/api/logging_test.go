@@ -0,0 +1,153 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/testutil"
+	"github.com/Anning01/user-management/pkg/logger"
+)
+
+func TestRequestLogFields(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	c := s.Login(t, user)
+	logs := testutil.CaptureLogs(t)
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+
+	c.Do(t, http.MethodGet, "/api/v1/users/me/articles", nil, http.Header{"X-Request-ID": {"req-log"}}).
+		Expect(t, http.StatusInternalServerError)
+
+	entry := logs.Find(t, "request failed")
+	if entry["level"] != "ERROR" || entry["request_id"] != "req-log" || entry["method"] != http.MethodGet ||
+		entry["route"] != "/api/v1/users/me/articles" || entry["user_id"] != float64(user.ID) {
+		t.Fatalf("unexpected log entry: %v", entry)
+	}
+	if _, ok := entry["latency"]; !ok || entry["error"] == nil {
+		t.Fatalf("expected latency and error in log entry: %v", entry)
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	s := testutil.NewServer(t)
+	article := s.CreateArticle(t, s.CreateUser(t))
+	logs := testutil.CaptureLogs(t)
+	s.DB.Logger = logger.NewGormLogger(time.Nanosecond)
+
+	s.Client().Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+
+	entry := logs.Find(t, "slow sql")
+	sql, ok := entry["sql"].(map[string]any)
+	if entry["level"] != "WARN" || !ok || sql["query"] == "" || entry["route"] != "/api/v1/articles/:id" {
+		t.Fatalf("unexpected slow query log: %v", entry)
+	}
+
+	// 日志中的 SQL 不包含绑定的参数值
+	s.DB.Logger = logger.NewGormLogger(time.Nanosecond)
+	logs = testutil.CaptureLogs(t)
+	s.Client().Post(t, "/api/v1/users/login", map[string]string{"email": "leak@example.com", "password": "secret"}).
+		Expect(t, http.StatusUnauthorized)
+	entries := logs.FindAll(t, "slow sql")
+	if len(entries) == 0 {
+		t.Fatal("expected slow sql logs")
+	}
+	for _, entry := range entries {
+		query, _ := entry["sql"].(map[string]any)["query"].(string)
+		if strings.Contains(query, "leak@example.com") || !strings.Contains(query, "?") {
+			t.Fatalf("expected parameterized query, got %q", query)
+		}
+	}
+
+	// 未超过阈值的 SQL 只在 debug 级别记录
+	s.DB.Logger = logger.NewGormLogger(time.Hour)
+	logs = testutil.CaptureLogs(t)
+	s.Client().Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	if entry := logs.Find(t, "sql"); entry["level"] != "DEBUG" {
+		t.Fatalf("unexpected sql log: %v", entry)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	// 未传入时生成请求ID，响应头与错误响应中的ID一致
+	resp := c.Get(t, "/api/v1/articles/999")
+	id := resp.Header.Get("X-Request-ID")
+	if len(id) != 32 {
+		t.Fatalf("expected generated request id, got %q", id)
+	}
+	if problem := resp.ExpectCode(t, http.StatusNotFound, "article_not_found"); problem.RequestID != id {
+		t.Fatalf("expected request id %q in problem, got %q", id, problem.RequestID)
+	}
+
+	resp = c.Do(t, http.MethodGet, "/health", nil, http.Header{"X-Request-ID": {"gateway-42"}}).Expect(t, http.StatusOK)
+	if got := resp.Header.Get("X-Request-ID"); got != "gateway-42" {
+		t.Fatalf("expected request id to be echoed, got %q", got)
+	}
+
+	// 不合法的请求ID被替换
+	resp = c.Do(t, http.MethodGet, "/health", nil, http.Header{"X-Request-ID": {"bad id"}}).Expect(t, http.StatusOK)
+	if got := resp.Header.Get("X-Request-ID"); got == "bad id" || len(got) != 32 {
+		t.Fatalf("expected invalid request id to be replaced, got %q", got)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	article := s.CreateArticle(t, user)
+	c := s.Login(t, user)
+	logs := testutil.CaptureLogs(t)
+
+	resp := c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	entry := logs.Find(t, "request")
+	if entry["level"] != "INFO" || entry["status"] != float64(http.StatusOK) || entry["route"] != "/api/v1/articles/:id" ||
+		entry["request_id"] != resp.Header.Get("X-Request-ID") || entry["user_id"] != float64(user.ID) {
+		t.Fatalf("unexpected access log: %v", entry)
+	}
+	if _, ok := entry["latency"]; !ok || entry["size"] != float64(len(resp.Body)) {
+		t.Fatalf("expected latency and size in access log: %v", entry)
+	}
+
+	// 错误响应记录最终状态码，健康检查不记录
+	logs = testutil.CaptureLogs(t)
+	c.Get(t, "/api/v1/articles/999").Expect(t, http.StatusNotFound)
+	c.Get(t, "/health").Expect(t, http.StatusOK)
+	entries := logs.FindAll(t, "request")
+	if len(entries) != 1 || entries[0]["level"] != "WARN" || entries[0]["status"] != float64(http.StatusNotFound) {
+		t.Fatalf("unexpected access logs: %v", entries)
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Log.Access.SampleRate = 0
+	})
+	c := s.Login(t, s.CreateUser(t))
+	logs := testutil.CaptureLogs(t)
+
+	c.Get(t, "/api/v1/articles").Expect(t, http.StatusOK)
+	if entries := logs.FindAll(t, "request"); len(entries) != 0 {
+		t.Fatalf("expected sampled out access log, got %v", entries)
+	}
+
+	// 服务端错误不受抽样影响
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+	c.Get(t, "/api/v1/users/me/articles").Expect(t, http.StatusInternalServerError)
+	if entry := logs.Find(t, "request"); entry["level"] != "ERROR" || entry["status"] != float64(http.StatusInternalServerError) {
+		t.Fatalf("unexpected access log: %v", entry)
+	}
+}
@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	article := s.CreateArticle(t, user)
+	c := s.Login(t, user)
+
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	c.Get(t, articlePath(999, "")).Expect(t, http.StatusNotFound)
+	c.Get(t, "/api/v1/no-such-route").Expect(t, http.StatusNotFound)
+	s.Client().Post(t, "/api/v1/users/login", map[string]string{"email": user.Email, "password": "wrong-password"}).
+		Expect(t, http.StatusUnauthorized)
+	s.Client().Post(t, "/api/v1/users/register", map[string]string{
+		"username": "bob", "email": "bob@example.com", "password": "secret123",
+	}).Expect(t, http.StatusCreated)
+
+	resp := s.Client().Get(t, "/metrics").Expect(t, http.StatusOK)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected prometheus text format, got %q", ct)
+	}
+	body := string(resp.Body)
+	for _, want := range []string{
+		// 按路由模板而不是实际路径统计
+		`http_requests_total{method="GET",route="/api/v1/articles/:id",status="200"} 2`,
+		`http_requests_total{method="GET",route="/api/v1/articles/:id",status="404"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/api/v1/articles/:id",status="200"} 2`,
+		`user_logins_total{result="success"} 1`,
+		`user_logins_total{result="failure"} 1`,
+		// 测试数据中的用户同样通过用户服务注册
+		`user_registrations_total 2`,
+		`go_sql_max_open_connections{db_name=":memory:"} 1`,
+		"go_goroutines ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
+
+func TestMetricsAdminPort(t *testing.T) {
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Metrics.Port = "9090"
+	})
+	c := s.Client()
+	c.Get(t, "/health").Expect(t, http.StatusOK)
+
+	// 配置管理端口后 API 服务器不再提供指标
+	c.Get(t, "/metrics").ExpectCode(t, http.StatusNotFound, "route_not_found")
+
+	rec := httptest.NewRecorder()
+	s.Metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), `http_requests_total{method="GET",route="/health",status="200"} 1`) {
+		t.Fatalf("unexpected metrics: %s", body)
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Metrics.Enabled = false
+	})
+	c := s.Client()
+	c.Get(t, "/metrics").ExpectCode(t, http.StatusNotFound, "route_not_found")
+	c.Post(t, "/api/v1/users/register", map[string]string{
+		"username": "bob", "email": "bob@example.com", "password": "secret123",
+	}).Expect(t, http.StatusCreated)
+}
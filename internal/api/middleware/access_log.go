@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog 请求结束时记录一条 request 日志，包含状态码、响应大小、客户端地址等，
+// 请求ID、方法、路由、用户ID与耗时来自 LogContext 附加的日志字段。
+// 需在 ErrorHandler 之前注册，才能记录到错误响应的最终状态码。
+// 状态码小于 500 的请求按 SampleRate 抽样，SkipPaths 中的路径（路由模板或原始路径）不记录。
+func AccessLog(cfg *config.AccessLogConfig) gin.HandlerFunc {
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Enabled || skip[c.Request.URL.Path] || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError && !sampled(cfg.SampleRate) {
+			return
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		// c.Request 已被 LogContext 替换为携带日志字段的请求
+		logger.L().LogAttrs(c.Request.Context(), level, "request",
+			slog.Int("status", status),
+			slog.Int("size", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		)
+	}
+}
+
+func sampled(rate float64) bool {
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	default:
+		return rand.Float64() < rate
+	}
+}
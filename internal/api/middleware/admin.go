@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware 要求当前用户为管理员，需在 AuthMiddleware 之后使用
+func AdminMiddleware(userService service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			abort(c, errUnauthorized)
+			return
+		}
+
+		user, err := userService.GetUserByID(c.Request.Context(), userID.(uint))
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			abort(c, err)
+			return
+		}
+		if user == nil || !user.IsAdmin() {
+			abort(c, errAdminRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,39 +1,59 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/domain/errs"
 	"github.com/Anning01/user-management/pkg/security"
-	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	errMissingToken   = errs.Unauthorized("missing_token", "authorization header is required")
+	errMalformedToken = errs.Unauthorized("malformed_authorization", "authorization header format must be Bearer {token}")
+	errInvalidToken   = errs.Unauthorized("invalid_token", "invalid or expired token")
+	errAdminRequired  = errs.Forbidden("admin_required", "admin privileges required")
+	errUnauthorized   = errs.Unauthorized("unauthorized", "unauthorized")
+)
+
 func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
-			c.Abort()
+			abort(c, errMissingToken)
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header format must be Bearer {token}"})
-			c.Abort()
+			abort(c, errMalformedToken)
 			return
 		}
 
 		claims, err := security.ValidateToken(parts[1], cfg.SecretKey)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-			c.Abort()
+			abort(c, errInvalidToken.Wrap(err))
 			return
 		}
 
 		// 将用户ID存储在上下文中
-		c.Set("userID", claims.UserID)
+		setUserID(c, claims.UserID)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware 用于公开接口：携带有效令牌时在上下文中设置用户ID，
+// 未携带或令牌无效时按匿名用户继续处理。
+func OptionalAuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := security.ValidateToken(parts[1], cfg.SecretKey); err == nil {
+				setUserID(c, claims.UserID)
+			}
+		}
 		c.Next()
 	}
 }
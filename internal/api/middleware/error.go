@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Anning01/user-management/internal/domain/errs"
+	"github.com/Anning01/user-management/internal/util"
+	"github.com/Anning01/user-management/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType RFC 7807 错误响应的内容类型
+const ProblemContentType = "application/problem+json"
+
+// RequestIDKey 请求ID在 gin 上下文中的键
+const RequestIDKey = "requestID"
+
+// Problem RFC 7807 错误响应体
+// code 为稳定的错误码，客户端应据此判断错误类型；detail 为面向开发者的说明，文案可能调整。
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors 参数校验失败时各字段的错误，提示文案按 Accept-Language 翻译
+	Errors []util.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler 统一输出错误响应，需注册在 RequestID 之后（响应体带上请求ID）、
+// AccessLog 与 Metrics 之后（它们记录错误响应输出后的最终状态码），并先于 Recovery 与其余中间件注册
+// 处理器与中间件通过 c.Error 记录错误并中止请求，这里根据错误类别映射状态码，
+// 输出 application/problem+json 响应体。未知错误一律返回 500，原始错误只写入日志。
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// NotFound 未匹配到路由时返回的错误
+func NotFound(c *gin.Context) {
+	abort(c, errs.NotFound("route_not_found", "route not found"))
+}
+
+// abort 记录错误并中止请求，由 ErrorHandler 输出错误响应
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func writeProblem(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "internal_error"
+	var fields []util.FieldError
+	if e := errs.As(err); e != nil && e.Kind != errs.KindInternal {
+		status, code = kindStatus(e.Kind), e.Code
+		if e.Kind == errs.KindValidation {
+			fields = util.FieldErrors(e, Localizer(c).Locale())
+		}
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		status, code = http.StatusServiceUnavailable, "timeout"
+	}
+
+	if status >= http.StatusInternalServerError {
+		logger.Error(c.Request.Context(), "request failed", logger.Err(err))
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    ErrorMessage(c, err),
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: requestID(c),
+		Errors:    fields,
+	})
+}
+
+// ErrorMessage 返回按当前请求语言翻译的错误提示，未知错误不暴露原始信息。
+// 领域错误按 Key（为空时为 Code）查找消息目录，目录中没有时使用错误自带的英文描述。
+func ErrorMessage(c *gin.Context, err error) string {
+	l := Localizer(c)
+	e := errs.As(err)
+	switch {
+	case e != nil && e.Kind != errs.KindInternal:
+		key := e.Key
+		if key == "" {
+			key = e.Code
+		}
+		if msg, ok := l.Lookup("error."+key, e.Params); ok {
+			return msg
+		}
+		return e.Message
+	case errors.Is(err, context.DeadlineExceeded):
+		return l.T("error.timeout", nil)
+	default:
+		return l.T("error.internal_error", nil)
+	}
+}
+
+// kindStatus 错误类别对应的 HTTP 状态码
+func kindStatus(kind errs.Kind) int {
+	switch kind {
+	case errs.KindValidation:
+		return http.StatusBadRequest
+	case errs.KindUnauthorized:
+		return http.StatusUnauthorized
+	case errs.KindForbidden:
+		return http.StatusForbidden
+	case errs.KindNotFound:
+		return http.StatusNotFound
+	case errs.KindConflict:
+		return http.StatusConflict
+	case errs.KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case errs.KindUnsupported:
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestID 当前请求的ID，由 RequestID 中间件设置
+func requestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
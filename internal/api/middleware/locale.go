@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	localeResolverKey = "localeResolver"
+	localizerKey      = "localizer"
+)
+
+// UserLocaleFunc 返回用户保存的语言偏好，未设置或查询失败时返回空字符串
+type UserLocaleFunc func(ctx context.Context, userID uint) string
+
+type localeResolver struct {
+	bundle     *i18n.Bundle
+	userLocale UserLocaleFunc
+}
+
+// Locale 为请求协商响应文案使用的语言，需紧随 ErrorHandler 注册。
+// 语言在第一次输出文案时才确定，此时认证中间件已设置用户ID：
+// 已登录且设置了语言偏好的用户使用偏好，否则按 Accept-Language 选择，都不支持时使用默认语言。
+func Locale(bundle *i18n.Bundle, userLocale UserLocaleFunc) gin.HandlerFunc {
+	resolver := &localeResolver{bundle: bundle, userLocale: userLocale}
+	return func(c *gin.Context) {
+		c.Set(localeResolverKey, resolver)
+		c.Next()
+	}
+}
+
+// Localizer 当前请求的本地化器，结果在请求内缓存
+func Localizer(c *gin.Context) *i18n.Localizer {
+	if l, ok := c.Get(localizerKey); ok {
+		return l.(*i18n.Localizer)
+	}
+
+	v, ok := c.Get(localeResolverKey)
+	if !ok {
+		return fallbackLocalizer
+	}
+	resolver := v.(*localeResolver)
+
+	var tags []string
+	if userID, ok := c.Get("userID"); ok && resolver.userLocale != nil {
+		tags = append(tags, resolver.userLocale(c.Request.Context(), userID.(uint)))
+	}
+	tags = append(tags, i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+	l := resolver.bundle.Localizer(resolver.bundle.Match(tags...))
+	c.Set(localizerKey, l)
+	return l
+}
+
+// Bundle 当前请求使用的消息目录，不会触发语言协商
+func Bundle(c *gin.Context) *i18n.Bundle {
+	if v, ok := c.Get(localeResolverKey); ok {
+		return v.(*localeResolver).bundle
+	}
+	return fallbackLocalizer.Bundle()
+}
+
+// fallbackLocalizer 未注册 Locale 中间件时使用内置英文目录
+var fallbackLocalizer = func() *i18n.Localizer {
+	bundle, err := i18n.NewBundle(i18n.Fallback, "")
+	if err != nil {
+		panic(err)
+	}
+	return bundle.Localizer(i18n.Fallback)
+}()
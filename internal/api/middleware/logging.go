@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/Anning01/user-management/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogContext 为请求上下文附加日志字段：请求ID、方法与路由模板，认证后再追加用户ID。
+// 之后使用请求上下文记录的日志（包括 SQL 日志）都会带上这些字段与请求已耗时间 latency。
+func LogContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attrs := make([]slog.Attr, 0, 3)
+		if id := requestID(c); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		attrs = append(attrs, slog.String("method", c.Request.Method))
+		// 未匹配到路由时没有路由模板，记录原始路径
+		if route := c.FullPath(); route != "" {
+			attrs = append(attrs, slog.String("route", route))
+		} else {
+			attrs = append(attrs, slog.String("path", c.Request.URL.Path))
+		}
+
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), attrs...))
+		c.Next()
+	}
+}
+
+// setUserID 在 gin 上下文中设置当前用户ID，并追加到请求的日志字段
+func setUserID(c *gin.Context, userID uint) {
+	c.Set("userID", userID)
+	logger.AddAttrs(c.Request.Context(), slog.Uint64("user_id", uint64(userID)))
+}
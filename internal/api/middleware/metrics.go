@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/Anning01/user-management/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute 未匹配到路由的请求统一使用的路由标签
+const unmatchedRoute = "unmatched"
+
+// Metrics 按方法、路由模板与状态码记录请求数与耗时，
+// 需在 ErrorHandler 之前注册，才能记录到错误响应的最终状态码
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery 将处理器中的 panic 转换为 500 错误，由 ErrorHandler 输出错误响应并连同调用栈写入日志，
+// 需在 ErrorHandler 与 LogContext 之后注册
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		abort(c, fmt.Errorf("panic: %v\n%s", recovered, debug.Stack()))
+	})
+}
@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestDataExport(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	s.CreateArticle(t, user)
+	c := s.Login(t, user)
+
+	var requested struct {
+		Export domain.DataExport `json:"export"`
+	}
+	c.Post(t, "/api/v1/users/me/export", nil).Expect(t, http.StatusAccepted).Decode(t, &requested)
+	if requested.Export.Status != domain.ExportStatusPending {
+		t.Fatalf("unexpected export: %+v", requested.Export)
+	}
+	c.Post(t, "/api/v1/users/me/export", nil).ExpectError(t, http.StatusConflict, "export already in progress")
+
+	if _, err := s.Privacy.ProcessPendingExports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var export domain.DataExport
+	exportPath := fmt.Sprintf("/api/v1/users/me/exports/%d", requested.Export.ID)
+	c.Get(t, exportPath).Expect(t, http.StatusOK).Decode(t, &export)
+	if export.Status != domain.ExportStatusReady || export.URL == "" {
+		t.Fatalf("expected ready export with url: %+v", export)
+	}
+	archive := s.Client().Get(t, export.URL).Expect(t, http.StatusOK)
+	if string(archive.Body[:2]) != "PK" {
+		t.Fatal("expected a zip archive")
+	}
+
+	var list struct {
+		Exports []domain.DataExport `json:"exports"`
+	}
+	c.Get(t, "/api/v1/users/me/exports").Expect(t, http.StatusOK).Decode(t, &list)
+	if len(list.Exports) != 1 {
+		t.Fatalf("expected 1 export, got %d", len(list.Exports))
+	}
+
+	s.Login(t, s.CreateUser(t)).Get(t, exportPath).ExpectError(t, http.StatusNotFound, "export not found")
+	c.Get(t, "/api/v1/users/me/exports/abc").ExpectError(t, http.StatusBadRequest, "invalid export id")
+	s.Client().Post(t, "/api/v1/users/me/export", nil).Expect(t, http.StatusUnauthorized)
+}
+
+func TestErasure(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	c := s.Login(t, user)
+
+	c.Get(t, "/api/v1/users/me/erasure").ExpectError(t, http.StatusNotFound, "erasure request not found")
+	c.Delete(t, "/api/v1/users/me/erasure").ExpectError(t, http.StatusNotFound, "erasure request not found")
+	c.Post(t, "/api/v1/users/me/erasure", map[string]any{}).Expect(t, http.StatusBadRequest)
+	c.Post(t, "/api/v1/users/me/erasure", map[string]any{"password": "wrong"}).ExpectError(t, http.StatusForbidden, "invalid password")
+
+	var scheduled struct {
+		Erasure domain.ErasureRequest `json:"erasure"`
+	}
+	c.Post(t, "/api/v1/users/me/erasure", map[string]any{"password": user.Password}).
+		Expect(t, http.StatusAccepted).Decode(t, &scheduled)
+	if scheduled.Erasure.Status != domain.ErasureStatusPending || !scheduled.Erasure.ScheduledAt.After(time.Now().Add(13*24*time.Hour)) {
+		t.Fatalf("unexpected erasure request: %+v", scheduled.Erasure)
+	}
+	c.Post(t, "/api/v1/users/me/erasure", map[string]any{"password": user.Password}).
+		ExpectError(t, http.StatusConflict, "erasure already requested")
+	c.Get(t, "/api/v1/users/me/erasure").Expect(t, http.StatusOK)
+
+	// 冷静期内可以撤销
+	c.Delete(t, "/api/v1/users/me/erasure").Expect(t, http.StatusOK)
+	if n, err := s.Privacy.ExecuteDueErasures(context.Background(), time.Now().Add(15*24*time.Hour)); err != nil || n != 0 {
+		t.Fatalf("expected no due erasures, got %d, %v", n, err)
+	}
+	c.Get(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+}
+
+func TestErasureExecution(t *testing.T) {
+	s := testutil.NewServer(t)
+	erased := s.CreateUser(t)
+	anonymized := s.CreateUser(t)
+	erasedArticle := s.CreateArticle(t, erased)
+	keptArticle := s.CreateArticle(t, anonymized)
+
+	// 被删除用户在保留的文章下的评论：无回复的评论、只有自己回复的评论链、有他人回复的评论
+	reader := s.Login(t, s.CreateUser(t))
+	ec := s.Login(t, erased)
+	leaf := createComment(t, ec, keptArticle.ID, map[string]any{"content": "secret leaf"})
+	chain := createComment(t, ec, keptArticle.ID, map[string]any{"content": "secret root"})
+	chainReply := createComment(t, ec, keptArticle.ID, map[string]any{"content": "secret reply", "parent_id": chain.ID})
+	parent := createComment(t, ec, keptArticle.ID, map[string]any{"content": "secret parent"})
+	reply := createComment(t, reader, keptArticle.ID, map[string]any{"content": "public reply", "parent_id": parent.ID})
+	reader.Post(t, articlePath(keptArticle.ID, fmt.Sprintf("/comments/%d/flag", leaf.ID)), nil).Expect(t, http.StatusOK)
+
+	s.Login(t, erased).Post(t, "/api/v1/users/me/erasure", map[string]any{"password": erased.Password}).
+		Expect(t, http.StatusAccepted)
+	s.Login(t, anonymized).Post(t, "/api/v1/users/me/erasure", map[string]any{"password": anonymized.Password, "keep_articles": true}).
+		Expect(t, http.StatusAccepted)
+
+	if n, err := s.Privacy.ExecuteDueErasures(context.Background(), time.Now().Add(15*24*time.Hour)); err != nil || n != 2 {
+		t.Fatalf("expected 2 erasures, got %d, %v", n, err)
+	}
+
+	for _, user := range []*domain.User{erased, anonymized} {
+		s.Client().Post(t, "/api/v1/users/login", map[string]string{"email": user.Email, "password": user.Password}).
+			Expect(t, http.StatusUnauthorized)
+	}
+	s.Client().Get(t, articlePath(erasedArticle.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+
+	// 保留的文章归属于匿名化的账号
+	var got domain.Article
+	s.Client().Get(t, articlePath(keptArticle.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if got.Author.Username != fmt.Sprintf("%s%d", domain.DeletedUserPrefix, anonymized.ID) {
+		t.Fatalf("expected anonymized author, got %q", got.Author.Username)
+	}
+
+	// 没有回复的评论连同举报记录彻底删除，有回复的评论在数据库中不再保留原文
+	var remaining []domain.Comment
+	if err := s.DB.Unscoped().Where("article_id = ?", keptArticle.ID).Order("id").Find(&remaining).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 || remaining[0].ID != parent.ID || remaining[1].ID != reply.ID {
+		t.Fatalf("expected only the replied comment and its reply to remain, got %+v", remaining)
+	}
+	if remaining[0].Content != "" || !remaining[0].DeletedAt.Valid {
+		t.Fatalf("expected blanked and deleted comment, got %+v", remaining[0])
+	}
+	if remaining[1].Content != "public reply" || remaining[1].DeletedAt.Valid {
+		t.Fatalf("expected reply to be kept, got %+v", remaining[1])
+	}
+	var flags int64
+	if err := s.DB.Model(&domain.CommentFlag{}).Where("comment_id IN ?", []uint{leaf.ID, chain.ID, chainReply.ID}).Count(&flags).Error; err != nil {
+		t.Fatal(err)
+	}
+	if flags != 0 {
+		t.Fatalf("expected flags of purged comments to be removed, got %d", flags)
+	}
+	if got.CommentCount != 1 {
+		t.Fatalf("expected comment count 1, got %d", got.CommentCount)
+	}
+}
+
+func TestErasureTimeZone(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	s.Login(t, user).Post(t, "/api/v1/users/me/erasure", map[string]any{"password": user.Password}).
+		Expect(t, http.StatusAccepted)
+
+	// 冷静期结束前一小时不执行，与进程时区无关
+	coolingOff := 14 * 24 * time.Hour
+	if n, err := s.Privacy.ExecuteDueErasures(context.Background(), time.Now().UTC().Add(coolingOff-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("expected no due erasures before the cooling-off period ends, got %d, %v", n, err)
+	}
+	if n, err := s.Privacy.ExecuteDueErasures(context.Background(), time.Now().Add(coolingOff+time.Hour)); err != nil || n != 1 {
+		t.Fatalf("expected 1 due erasure, got %d, %v", n, err)
+	}
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/Anning01/user-management/internal/api/handlers"
 	"github.com/Anning01/user-management/internal/api/middleware"
 	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/i18n"
+	"github.com/Anning01/user-management/internal/metrics"
+	"github.com/Anning01/user-management/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,18 +18,96 @@ func SetupRoutes(
 	r *gin.Engine,
 	userHandler *handlers.UserHandler,
 	articleHandler *handlers.ArticleHandler,
+	commentHandler *handlers.CommentHandler,
+	engagementHandler *handlers.EngagementHandler,
+	followHandler *handlers.FollowHandler,
+	feedHandler *handlers.FeedHandler,
+	uploadHandler *handlers.UploadHandler,
+	trashHandler *handlers.TrashHandler,
+	privacyHandler *handlers.PrivacyHandler,
+	transferHandler *handlers.TransferHandler,
+	seriesHandler *handlers.SeriesHandler,
+	userService service.UserService,
 	jwtConfig *config.JWTConfig,
+	accessLog *config.AccessLogConfig,
+	metricsConfig *config.MetricsConfig,
+	m *metrics.Metrics,
+	bundle *i18n.Bundle,
 ) {
+	// 请求ID，写入响应头、错误响应与日志
+	r.Use(middleware.RequestID())
+	// 访问日志，在错误响应输出之后记录最终状态码
+	r.Use(middleware.AccessLog(accessLog))
+	// 请求数与耗时指标，同样需要记录最终状态码
+	if m != nil {
+		r.Use(middleware.Metrics(m))
+	}
+	// 统一输出错误响应，需要先于业务相关的中间件注册
+	r.Use(middleware.ErrorHandler())
+	// 请求日志字段，之后的中间件与处理器记录的日志都会带上请求ID、路由与用户ID
+	r.Use(middleware.LogContext())
+	// panic 转换为 500 错误响应
+	r.Use(middleware.Recovery())
+	// 提示文案的语言协商，已登录用户优先使用其语言偏好
+	r.Use(middleware.Locale(bundle, func(ctx context.Context, userID uint) string {
+		user, err := userService.GetUserByID(ctx, userID)
+		if err != nil {
+			return ""
+		}
+		return user.Locale
+	}))
+	r.NoRoute(middleware.NotFound)
+
+	// Prometheus 指标，配置了管理端口时改由管理端口提供
+	if m != nil && metricsConfig.Port == "" {
+		r.GET("/metrics", gin.WrapH(m.Handler()))
+	}
+
+	// 健康检查，默认不记录访问日志
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
 	// 公开路由
+	// 订阅源
+	feeds := r.Group("/feeds")
+	{
+		feeds.GET("/articles.rss", feedHandler.ArticlesRSS)
+		feeds.GET("/articles.atom", feedHandler.ArticlesAtom)
+		feeds.GET("/articles.json", feedHandler.ArticlesJSON)
+		feeds.GET("/authors/:id/articles.rss", feedHandler.AuthorRSS)
+		feeds.GET("/authors/:id/articles.atom", feedHandler.AuthorAtom)
+		feeds.GET("/authors/:id/articles.json", feedHandler.AuthorJSON)
+	}
+
+	// 本地存储文件下载（需要签名）
+	r.GET("/files/*key", uploadHandler.ServeFile)
+
+	// 公开路由（携带令牌时识别当前用户，用于 liked_by_me 等字段）
 	public := r.Group("/api/v1")
+	public.Use(middleware.OptionalAuthMiddleware(jwtConfig))
 	{
 		// 用户相关
 		public.POST("/users/register", userHandler.Register)
 		public.POST("/users/login", userHandler.Login)
+		public.GET("/users/:id", followHandler.GetUserProfile)
+		public.GET("/users/:id/followers", followHandler.ListFollowers)
+		public.GET("/users/:id/following", followHandler.ListFollowing)
+		public.GET("/users/:id/series", seriesHandler.ListUserSeries)
 
 		// 文章相关（公开访问的）
 		public.GET("/articles", articleHandler.ListArticles)
+		public.GET("/articles/search", articleHandler.SearchArticles)
+		public.GET("/articles/by-slug/:slug", articleHandler.GetArticleBySlug)
 		public.GET("/articles/:id", articleHandler.GetArticle)
+
+		// 系列
+		public.GET("/series/:id", seriesHandler.GetSeries)
+
+		// 评论相关
+		public.GET("/articles/:id/comments", commentHandler.ListComments)
+		public.GET("/articles/:id/attachments", uploadHandler.ListAttachments)
+		public.GET("/users/:id/avatar", uploadHandler.GetAvatar)
 	}
 
 	// 需要认证的路由
@@ -40,5 +124,76 @@ func SetupRoutes(
 		protected.PUT("/articles/:id", articleHandler.UpdateArticle)
 		protected.DELETE("/articles/:id", articleHandler.DeleteArticle)
 		protected.GET("/users/me/articles", articleHandler.ListMyArticles)
+		protected.GET("/users/me/shared-articles", articleHandler.ListSharedArticles)
+		protected.GET("/users/me/articles/export", transferHandler.ExportMyArticles)
+		protected.POST("/users/me/articles/import", transferHandler.ImportMyArticles)
+		protected.PUT("/users/me/avatar", uploadHandler.UploadAvatar)
+		protected.DELETE("/users/me/avatar", uploadHandler.DeleteAvatar)
+
+		// 文章协作者
+		protected.GET("/articles/:id/contributors", articleHandler.ListContributors)
+		protected.POST("/articles/:id/contributors", articleHandler.AddContributor)
+		protected.DELETE("/articles/:id/contributors/:user_id", articleHandler.RemoveContributor)
+
+		// 系列
+		protected.POST("/series", seriesHandler.CreateSeries)
+		protected.PUT("/series/:id", seriesHandler.UpdateSeries)
+		protected.DELETE("/series/:id", seriesHandler.DeleteSeries)
+		protected.POST("/series/:id/articles", seriesHandler.AddArticle)
+		protected.PUT("/series/:id/articles", seriesHandler.ReorderArticles)
+		protected.DELETE("/series/:id/articles/:article_id", seriesHandler.RemoveArticle)
+
+		// 回收站
+		protected.GET("/users/me/trash", trashHandler.ListMyTrash)
+		protected.POST("/articles/:id/restore", trashHandler.RestoreArticle)
+
+		// 个人数据导出与账号注销
+		protected.POST("/users/me/export", privacyHandler.RequestExport)
+		protected.GET("/users/me/exports", privacyHandler.ListExports)
+		protected.GET("/users/me/exports/:id", privacyHandler.GetExport)
+		protected.POST("/users/me/erasure", privacyHandler.RequestErasure)
+		protected.GET("/users/me/erasure", privacyHandler.GetErasure)
+		protected.DELETE("/users/me/erasure", privacyHandler.CancelErasure)
+
+		// 附件
+		protected.POST("/articles/:id/attachments", uploadHandler.UploadAttachment)
+		protected.DELETE("/articles/:id/attachments/:attachment_id", uploadHandler.DeleteAttachment)
+
+		// 评论相关
+		protected.POST("/articles/:id/comments", commentHandler.CreateComment)
+		protected.PUT("/articles/:id/comments/:comment_id", commentHandler.UpdateComment)
+		protected.DELETE("/articles/:id/comments/:comment_id", commentHandler.DeleteComment)
+		protected.POST("/articles/:id/comments/:comment_id/flag", commentHandler.FlagComment)
+
+		// 点赞与收藏
+		protected.POST("/articles/:id/like", engagementHandler.LikeArticle)
+		protected.DELETE("/articles/:id/like", engagementHandler.UnlikeArticle)
+		protected.POST("/articles/:id/bookmark", engagementHandler.BookmarkArticle)
+		protected.DELETE("/articles/:id/bookmark", engagementHandler.UnbookmarkArticle)
+		protected.GET("/users/me/bookmarks", engagementHandler.ListMyBookmarks)
+
+		// 关注与动态
+		protected.POST("/users/:id/follow", followHandler.Follow)
+		protected.DELETE("/users/:id/follow", followHandler.Unfollow)
+		protected.GET("/users/me/feed", articleHandler.ListFeed)
+	}
+
+	// 管理员路由
+	admin := r.Group("/api/v1")
+	admin.Use(middleware.AuthMiddleware(jwtConfig), middleware.AdminMiddleware(userService))
+	{
+		// 评论审核
+		admin.GET("/moderation/comments", commentHandler.ListFlaggedComments)
+		admin.POST("/moderation/comments/:id/approve", commentHandler.ApproveComment)
+		admin.POST("/moderation/comments/:id/reject", commentHandler.RejectComment)
+
+		// 回收站
+		admin.GET("/trash/articles", trashHandler.ListDeletedArticles)
+		admin.GET("/trash/users", trashHandler.ListDeletedUsers)
+		admin.POST("/trash/users/:id/restore", trashHandler.RestoreUser)
+
+		// 文章批量导入导出
+		admin.GET("/articles/export", transferHandler.ExportArticles)
+		admin.POST("/articles/import", transferHandler.ImportArticles)
 	}
 }
@@ -0,0 +1,153 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func createSeries(t *testing.T, c *testutil.Client, title string) *domain.Series {
+	t.Helper()
+	var created struct {
+		Series domain.Series `json:"series"`
+	}
+	c.Post(t, "/api/v1/series", map[string]string{"title": title, "description": "A series for testing."}).
+		Expect(t, http.StatusCreated).Decode(t, &created)
+	return &created.Series
+}
+
+func seriesPath(id uint, suffix string) string {
+	return fmt.Sprintf("/api/v1/series/%d%s", id, suffix)
+}
+
+func TestSeries(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	first := s.CreateArticle(t, author)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	third := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+
+	series := createSeries(t, c, "Go From Scratch")
+	for _, article := range []*domain.Article{first, draft, third} {
+		c.Post(t, seriesPath(series.ID, "/articles"), map[string]any{"article_id": article.ID}).Expect(t, http.StatusCreated)
+	}
+
+	// 草稿对读者隐藏，导航跳过草稿
+	var got domain.Series
+	s.Client().Get(t, seriesPath(series.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if len(got.Articles) != 2 {
+		t.Fatalf("expected 2 published articles, got %d", len(got.Articles))
+	}
+	c.Get(t, seriesPath(series.ID, "")).Decode(t, &got)
+	if len(got.Articles) != 3 {
+		t.Fatalf("expected the author to see 3 articles, got %d", len(got.Articles))
+	}
+
+	var article domain.Article
+	s.Client().Get(t, articlePath(first.ID, "")).Decode(t, &article)
+	if article.Series == nil || article.Series.Position != 1 || article.Series.Next == nil || article.Series.Next.ID != third.ID {
+		t.Fatalf("unexpected series navigation: %+v", article.Series)
+	}
+
+	// 调整顺序
+	c.Put(t, seriesPath(series.ID, "/articles"), map[string]any{"article_ids": []uint{third.ID, draft.ID, first.ID}}).Expect(t, http.StatusOK)
+	s.Client().Get(t, articlePath(first.ID, "")).Decode(t, &article)
+	if article.Series.Previous == nil || article.Series.Previous.ID != third.ID || article.Series.Next != nil {
+		t.Fatalf("unexpected navigation after reorder: %+v", article.Series)
+	}
+
+	c.Delete(t, seriesPath(series.ID, fmt.Sprintf("/articles/%d", third.ID))).Expect(t, http.StatusOK)
+	var removed domain.Article
+	s.Client().Get(t, articlePath(third.ID, "")).Decode(t, &removed)
+	if removed.Series != nil {
+		t.Fatalf("removed article should not have series navigation: %+v", removed.Series)
+	}
+
+	c.Put(t, seriesPath(series.ID, ""), map[string]string{"title": "Go From Zero"}).Expect(t, http.StatusOK)
+	var list struct {
+		Series []domain.Series `json:"series"`
+		Total  int64           `json:"total"`
+	}
+	s.Client().Get(t, fmt.Sprintf("/api/v1/users/%d/series", author.ID)).Expect(t, http.StatusOK).Decode(t, &list)
+	if list.Total != 1 || list.Series[0].Title != "Go From Zero" {
+		t.Fatalf("unexpected series list: %+v", list)
+	}
+
+	// 删除系列后文章保留
+	c.Delete(t, seriesPath(series.ID, "")).Expect(t, http.StatusOK)
+	s.Client().Get(t, seriesPath(series.ID, "")).ExpectError(t, http.StatusNotFound, "series not found")
+	s.Client().Get(t, articlePath(first.ID, "")).Expect(t, http.StatusOK)
+}
+
+func TestSeriesErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	other := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	othersArticle := s.CreateArticle(t, other)
+	c := s.Login(t, author)
+	series := createSeries(t, c, "My Series")
+	articlesPath := seriesPath(series.ID, "/articles")
+
+	c.Post(t, "/api/v1/series", map[string]string{"title": "No"}).Expect(t, http.StatusBadRequest)
+	s.Client().Post(t, "/api/v1/series", map[string]string{"title": "Anonymous"}).Expect(t, http.StatusUnauthorized)
+	s.Client().Get(t, "/api/v1/series/abc").ExpectError(t, http.StatusBadRequest, "invalid series id")
+	s.Client().Get(t, "/api/v1/series/999").ExpectError(t, http.StatusNotFound, "series not found")
+	s.Client().Get(t, "/api/v1/users/abc/series").ExpectError(t, http.StatusBadRequest, "invalid user id")
+
+	// 只有系列作者可以修改系列
+	otherClient := s.Login(t, other)
+	otherClient.Put(t, seriesPath(series.ID, ""), map[string]string{"title": "Stolen Series"}).ExpectError(t, http.StatusForbidden, "permission denied")
+	otherClient.Delete(t, seriesPath(series.ID, "")).ExpectError(t, http.StatusForbidden, "permission denied")
+	otherClient.Post(t, articlesPath, map[string]any{"article_id": othersArticle.ID}).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Put(t, "/api/v1/series/999", map[string]string{"title": "Missing Series"}).ExpectError(t, http.StatusNotFound, "series not found")
+
+	// 只能加入自己可以编辑的文章
+	c.Post(t, articlesPath, map[string]any{"article_id": othersArticle.ID}).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Post(t, articlesPath, map[string]any{"article_id": 999}).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Post(t, articlesPath, map[string]any{}).Expect(t, http.StatusBadRequest)
+
+	c.Post(t, articlesPath, map[string]any{"article_id": article.ID}).Expect(t, http.StatusCreated)
+	c.Post(t, articlesPath, map[string]any{"article_id": article.ID}).ExpectError(t, http.StatusConflict, "article already in a series")
+	another := createSeries(t, c, "Another Series")
+	c.Post(t, seriesPath(another.ID, "/articles"), map[string]any{"article_id": article.ID}).
+		ExpectError(t, http.StatusConflict, "article already in a series")
+
+	c.Put(t, articlesPath, map[string]any{"article_ids": []uint{article.ID, othersArticle.ID}}).
+		ExpectError(t, http.StatusBadRequest, "article ids do not match series")
+	c.Delete(t, seriesPath(series.ID, fmt.Sprintf("/articles/%d", othersArticle.ID))).ExpectError(t, http.StatusNotFound, "article not in series")
+	c.Delete(t, seriesPath(series.ID, "/articles/abc")).ExpectError(t, http.StatusBadRequest, "invalid article id")
+}
+
+func TestSeriesReorderWithTrashedArticle(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	first := s.CreateArticle(t, author)
+	trashed := s.CreateArticle(t, author)
+	third := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+
+	series := createSeries(t, c, "Trash Aware")
+	for _, article := range []*domain.Article{first, trashed, third} {
+		c.Post(t, seriesPath(series.ID, "/articles"), map[string]any{"article_id": article.ID}).Expect(t, http.StatusCreated)
+	}
+	c.Delete(t, articlePath(trashed.ID, "")).Expect(t, http.StatusOK)
+
+	// 只需列出未删除的文章，回收站中的文章不能参与排序
+	articlesPath := seriesPath(series.ID, "/articles")
+	c.Put(t, articlesPath, map[string]any{"article_ids": []uint{third.ID, trashed.ID, first.ID}}).
+		ExpectError(t, http.StatusBadRequest, "article ids do not match series")
+	c.Put(t, articlesPath, map[string]any{"article_ids": []uint{third.ID, first.ID}}).Expect(t, http.StatusOK)
+
+	// 恢复后的文章排在最后
+	c.Post(t, articlePath(trashed.ID, "/restore"), nil).Expect(t, http.StatusOK)
+	var got domain.Series
+	c.Get(t, seriesPath(series.ID, "")).Expect(t, http.StatusOK).Decode(t, &got)
+	if len(got.Articles) != 3 || got.Articles[0].ID != third.ID || got.Articles[1].ID != first.ID || got.Articles[2].ID != trashed.ID {
+		t.Fatalf("unexpected order after restore: %+v", got.Articles)
+	}
+}
@@ -0,0 +1,165 @@
+package api_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func jsonLines(t *testing.T, records ...any) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, record := range records {
+		if s, ok := record.(string); ok {
+			buf.WriteString(s + "\n")
+			continue
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+	return buf.Bytes()
+}
+
+func readRecords(t *testing.T, body []byte) []domain.ArticleRecord {
+	t.Helper()
+	var records []domain.ArticleRecord
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var record domain.ArticleRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode record %s: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExportArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+	bob := s.CreateUser(t)
+	s.CreateArticle(t, alice)
+	s.CreateArticle(t, alice, testutil.AsDraft())
+	s.CreateArticle(t, bob)
+	c := s.Login(t, alice)
+
+	resp := c.Get(t, "/api/v1/users/me/articles/export").Expect(t, http.StatusOK)
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	records := readRecords(t, resp.Body)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records including the draft, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.AuthorID != alice.ID {
+			t.Fatalf("exported article of another author: %+v", record)
+		}
+	}
+
+	zip := c.Get(t, "/api/v1/users/me/articles/export?format=markdown").Expect(t, http.StatusOK)
+	if zip.Header.Get("Content-Type") != "application/zip" || string(zip.Body[:2]) != "PK" {
+		t.Fatal("expected a zip archive")
+	}
+	c.Get(t, "/api/v1/users/me/articles/export?format=csv").ExpectError(t, http.StatusBadRequest, "unsupported transfer format")
+
+	// 管理员可以导出全部或指定作者的文章
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+	if n := len(readRecords(t, admin.Get(t, "/api/v1/articles/export").Expect(t, http.StatusOK).Body)); n != 3 {
+		t.Fatalf("expected 3 records, got %d", n)
+	}
+	if n := len(readRecords(t, admin.Get(t, fmt.Sprintf("/api/v1/articles/export?author_id=%d", bob.ID)).Body)); n != 1 {
+		t.Fatalf("expected 1 record of bob, got %d", n)
+	}
+	admin.Get(t, "/api/v1/articles/export?author_id=abc").ExpectError(t, http.StatusBadRequest, "invalid author id")
+	c.Get(t, "/api/v1/articles/export").ExpectError(t, http.StatusForbidden, "admin privileges required")
+}
+
+func TestImportArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	other := s.CreateUser(t)
+	c := s.Login(t, user)
+
+	file := jsonLines(t,
+		domain.ArticleRecord{ExternalID: "post-1", Title: "Imported One", Content: "Content of the first import."},
+		domain.ArticleRecord{ExternalID: "post-2", Title: "Imported Two", Content: "Content of the second import.", AuthorID: other.ID},
+		domain.ArticleRecord{ExternalID: "post-3", Title: "No", Content: "Title is too short."},
+		"{not json",
+	)
+
+	var report domain.ImportReport
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import?dry_run=true", "file", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusOK).Decode(t, &report)
+	if !report.DryRun || report.Created != 2 || report.Failed != 2 {
+		t.Fatalf("unexpected dry run report: %+v", report)
+	}
+	if list := listArticles(t, c, "/api/v1/users/me/articles"); list.Total != 0 {
+		t.Fatalf("dry run should not create articles, got %d", list.Total)
+	}
+
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import", "file", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusOK).Decode(t, &report)
+	if report.Total != 4 || report.Created != 2 || report.Failed != 2 || len(report.Errors) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Errors[0].Row != 3 || report.Errors[0].ExternalID != "post-3" || !strings.HasPrefix(report.Errors[1].Error, "invalid json") {
+		t.Fatalf("unexpected import errors: %+v", report.Errors)
+	}
+
+	// 普通用户导入时忽略 author_id，文章归属于自己
+	if list := listArticles(t, c, "/api/v1/users/me/articles"); list.Total != 2 {
+		t.Fatalf("expected 2 imported articles, got %d", list.Total)
+	}
+	if results := s.Client().Get(t, "/api/v1/articles/search?q=imported").JSON(t); results["total"] != float64(2) {
+		t.Fatalf("imported articles should be searchable: %v", results)
+	}
+
+	// 再次导入相同内容不会产生变化
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import", "file", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusOK).Decode(t, &report)
+	if report.Unchanged != 2 || report.Created != 0 || report.Updated != 0 {
+		t.Fatalf("unexpected report of repeated import: %+v", report)
+	}
+
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import?format=csv", "file", "articles.csv", "text/csv", file).
+		ExpectError(t, http.StatusBadRequest, "unsupported transfer format")
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import", "file", "articles.zip", "application/zip", file).
+		ExpectError(t, http.StatusBadRequest, "invalid zip file")
+	c.Upload(t, http.MethodPost, "/api/v1/users/me/articles/import", "upload", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusBadRequest)
+}
+
+func TestAdminImportArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+
+	file := jsonLines(t,
+		domain.ArticleRecord{ExternalID: "post-1", Title: "For The Author", Content: "Imported on behalf of the author.", AuthorID: author.ID},
+		domain.ArticleRecord{ExternalID: "post-2", Title: "Unknown Author", Content: "The author does not exist.", AuthorID: 999},
+	)
+
+	var report domain.ImportReport
+	admin.Upload(t, http.MethodPost, "/api/v1/articles/import", "file", "articles.jsonl", "application/x-ndjson", file).
+		Expect(t, http.StatusOK).Decode(t, &report)
+	if report.Created != 1 || report.Failed != 1 || report.Errors[0].Error != "author not found" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if list := listArticles(t, s.Login(t, author), "/api/v1/users/me/articles"); list.Total != 1 {
+		t.Fatalf("expected the article to belong to the author, got %d", list.Total)
+	}
+
+	s.Login(t, author).Upload(t, http.MethodPost, "/api/v1/articles/import", "file", "articles.jsonl", "application/x-ndjson", file).
+		ExpectError(t, http.StatusForbidden, "admin privileges required")
+}
@@ -0,0 +1,150 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+type trashList struct {
+	Articles []map[string]any `json:"articles"`
+	Users    []map[string]any `json:"users"`
+	Total    int64            `json:"total"`
+}
+
+func TestArticleTrash(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+
+	c.Delete(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+
+	var trash trashList
+	c.Get(t, "/api/v1/users/me/trash").Expect(t, http.StatusOK).Decode(t, &trash)
+	if trash.Total != 1 || trash.Articles[0]["deleted_at"] == nil || trash.Articles[0]["purge_at"] == nil {
+		t.Fatalf("unexpected trash: %+v", trash)
+	}
+	s.Login(t, s.CreateUser(t)).Get(t, "/api/v1/users/me/trash").Decode(t, &trash)
+	if trash.Total != 0 {
+		t.Fatalf("trash of other users should be empty, got %d", trash.Total)
+	}
+
+	restorePath := articlePath(article.ID, "/restore")
+	s.Login(t, s.CreateUser(t)).Post(t, restorePath, nil).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Post(t, "/api/v1/articles/abc/restore", nil).ExpectError(t, http.StatusBadRequest, "invalid article id")
+	c.Post(t, restorePath, nil).Expect(t, http.StatusOK)
+	c.Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	// 未删除的文章不在回收站中
+	c.Post(t, restorePath, nil).ExpectError(t, http.StatusNotFound, "article not found")
+
+	// 管理员可以恢复任何人的文章
+	c.Delete(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+	admin.Get(t, "/api/v1/trash/articles").Expect(t, http.StatusOK).Decode(t, &trash)
+	if trash.Total != 1 {
+		t.Fatalf("expected 1 deleted article, got %d", trash.Total)
+	}
+	admin.Post(t, restorePath, nil).Expect(t, http.StatusOK)
+
+	// 彻底删除后无法恢复
+	c.Delete(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+	if _, _, err := s.Trash.Purge(context.Background(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	c.Post(t, restorePath, nil).ExpectError(t, http.StatusNotFound, "article not found")
+	c.Get(t, "/api/v1/users/me/trash").Decode(t, &trash)
+	if trash.Total != 0 {
+		t.Fatalf("expected empty trash after purge, got %d", trash.Total)
+	}
+}
+
+func TestUserTrash(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	article := s.CreateArticle(t, user)
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+
+	s.Login(t, user).Delete(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+	// 用户的文章随用户一起删除
+	s.Client().Get(t, articlePath(article.ID, "")).ExpectError(t, http.StatusNotFound, "article not found")
+	// 作者恢复之前不能单独恢复文章
+	admin.Post(t, articlePath(article.ID, "/restore"), nil).ExpectError(t, http.StatusConflict, "author is deleted")
+	// 回收站中的用户仍然占用用户名
+	s.Client().Post(t, "/api/v1/users/register", map[string]string{
+		"username": user.Username,
+		"email":    "other@example.com",
+		"password": "secret123",
+	}).ExpectError(t, http.StatusConflict, "username or email already exists")
+
+	var trash trashList
+	admin.Get(t, "/api/v1/trash/users").Expect(t, http.StatusOK).Decode(t, &trash)
+	if trash.Total != 1 || trash.Users[0]["user"].(map[string]any)["username"] != user.Username {
+		t.Fatalf("unexpected deleted users: %+v", trash)
+	}
+
+	restorePath := fmt.Sprintf("/api/v1/trash/users/%d/restore", user.ID)
+	s.Login(t, s.CreateUser(t)).Post(t, restorePath, nil).ExpectError(t, http.StatusForbidden, "admin privileges required")
+	admin.Post(t, "/api/v1/trash/users/999/restore", nil).ExpectError(t, http.StatusNotFound, "user not found")
+	admin.Post(t, "/api/v1/trash/users/abc/restore", nil).ExpectError(t, http.StatusBadRequest, "invalid user id")
+	admin.Post(t, restorePath, nil).Expect(t, http.StatusOK)
+
+	s.Login(t, user).Get(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+	s.Client().Get(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+}
+
+func TestUserTrashSearchIndex(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	for i := 0; i < 3; i++ {
+		s.CreateArticle(t, user, testutil.WithTitle(fmt.Sprintf("Zephyr notes %d", i)))
+	}
+	admin := s.Login(t, s.CreateUser(t, testutil.AsAdmin()))
+	searchTotal := func() float64 {
+		t.Helper()
+		return s.Client().Get(t, "/api/v1/articles/search?q=zephyr&page_size=1").Expect(t, http.StatusOK).JSON(t)["total"].(float64)
+	}
+	if total := searchTotal(); total != 3 {
+		t.Fatalf("expected 3 results, got %v", total)
+	}
+
+	// 随用户删除的文章从索引中移除，后续分页的总数也不包含这些文章
+	s.Login(t, user).Delete(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+	if total := searchTotal(); total != 0 {
+		t.Fatalf("expected deleted user's articles to leave the index, got %v", total)
+	}
+
+	admin.Post(t, fmt.Sprintf("/api/v1/trash/users/%d/restore", user.ID), nil).Expect(t, http.StatusOK)
+	if total := searchTotal(); total != 3 {
+		t.Fatalf("expected restored articles to be indexed again, got %v", total)
+	}
+}
+
+func TestTrashPurgeTimeZone(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	s.Login(t, user).Delete(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+	s.Login(t, author).Delete(t, articlePath(article.ID, "")).Expect(t, http.StatusOK)
+
+	// 刚删除的数据不能因为进程时区与存储时区不同而提前清理
+	for _, before := range []time.Time{time.Now().Add(-time.Hour), time.Now().UTC().Add(-time.Hour)} {
+		articles, users, err := s.Trash.Purge(context.Background(), before)
+		if err != nil || articles != 0 || users != 0 {
+			t.Fatalf("expected nothing purged before %v, got %d articles, %d users, %v", before, articles, users, err)
+		}
+	}
+	articles, users, err := s.Trash.Purge(context.Background(), time.Now().Add(time.Hour))
+	if err != nil || articles != 1 || users != 1 {
+		t.Fatalf("expected expired trash to be purged, got %d articles, %d users, %v", articles, users, err)
+	}
+}
@@ -0,0 +1,243 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+	"github.com/Anning01/user-management/pkg/storage"
+)
+
+type attachmentList struct {
+	Attachments []domain.Attachment `json:"attachments"`
+}
+
+func pngImage(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// oversizedPNG 返回一张很小的 PNG，但 IHDR 中声明的尺寸为 side×side
+func oversizedPNG(t *testing.T, side uint32) []byte {
+	t.Helper()
+	data := pngImage(t, 1)
+	binary.BigEndian.PutUint32(data[16:20], side)
+	binary.BigEndian.PutUint32(data[20:24], side)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestAttachments(t *testing.T) {
+	s := testutil.NewServer(t)
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	c := s.Login(t, author)
+
+	var created struct {
+		Attachment domain.Attachment `json:"attachment"`
+	}
+	// 文件类型根据内容识别，而不是客户端声明的类型
+	c.Upload(t, http.MethodPost, articlePath(article.ID, "/attachments"), "file", "notes.txt", "image/png", []byte("plain text notes")).
+		Expect(t, http.StatusCreated).Decode(t, &created)
+	if !strings.HasPrefix(created.Attachment.ContentType, "text/plain") || created.Attachment.FileName != "notes.txt" {
+		t.Fatalf("unexpected attachment: %+v", created.Attachment)
+	}
+
+	var list attachmentList
+	s.Client().Get(t, articlePath(article.ID, "/attachments")).Expect(t, http.StatusOK).Decode(t, &list)
+	if len(list.Attachments) != 1 || list.Attachments[0].URL == "" {
+		t.Fatalf("unexpected attachments: %+v", list.Attachments)
+	}
+
+	// 下载地址带签名，篡改或缺少签名时拒绝访问
+	fileURL := list.Attachments[0].URL
+	resp := s.Client().Get(t, fileURL).Expect(t, http.StatusOK)
+	if string(resp.Body) != "plain text notes" || resp.Header.Get("Content-Disposition") != "attachment" {
+		t.Fatalf("unexpected file response: %s %v", resp.Body, resp.Header)
+	}
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Client().Get(t, u.Path).ExpectError(t, http.StatusForbidden, "invalid or expired signature")
+	q := u.Query()
+	q.Set("signature", "tampered")
+	s.Client().Get(t, u.Path+"?"+q.Encode()).ExpectError(t, http.StatusForbidden, "invalid or expired signature")
+	missing := s.Signer.Sign("attachments/missing.txt", time.Now().Add(time.Minute))
+	s.Client().Get(t, "/files/attachments/missing.txt?"+missing.Encode()).ExpectError(t, http.StatusNotFound, "file not found")
+
+	path := articlePath(article.ID, fmt.Sprintf("/attachments/%d", created.Attachment.ID))
+	s.Login(t, s.CreateUser(t)).Delete(t, path).ExpectError(t, http.StatusForbidden, "permission denied")
+	c.Delete(t, path).Expect(t, http.StatusOK)
+	c.Delete(t, path).ExpectError(t, http.StatusNotFound, "attachment not found")
+	s.Client().Get(t, fileURL).ExpectError(t, http.StatusNotFound, "file not found")
+}
+
+func TestAttachmentErrors(t *testing.T) {
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Upload.MaxSize = 1
+	})
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	draft := s.CreateArticle(t, author, testutil.AsDraft())
+	c := s.Login(t, author)
+	path := articlePath(article.ID, "/attachments")
+
+	c.Upload(t, http.MethodPost, path, "file", "big.txt", "text/plain", bytes.Repeat([]byte("a"), 1<<20+1)).
+		ExpectError(t, http.StatusRequestEntityTooLarge, "file too large")
+	c.Upload(t, http.MethodPost, path, "file", "archive.zip", "application/zip", []byte("PK\x03\x04\x14\x00\x00\x00\x08\x00")).
+		ExpectError(t, http.StatusUnsupportedMediaType, "file type not allowed")
+	c.Upload(t, http.MethodPost, path, "document", "notes.txt", "text/plain", []byte("notes")).Expect(t, http.StatusBadRequest)
+	c.Upload(t, http.MethodPost, "/api/v1/articles/999/attachments", "file", "notes.txt", "text/plain", []byte("notes")).
+		ExpectError(t, http.StatusNotFound, "article not found")
+	s.Login(t, s.CreateUser(t)).Upload(t, http.MethodPost, path, "file", "notes.txt", "text/plain", []byte("notes")).
+		ExpectError(t, http.StatusForbidden, "permission denied")
+
+	// 草稿的附件仅协作者可见
+	c.Upload(t, http.MethodPost, articlePath(draft.ID, "/attachments"), "file", "notes.txt", "text/plain", []byte("notes")).
+		Expect(t, http.StatusCreated)
+	c.Get(t, articlePath(draft.ID, "/attachments")).Expect(t, http.StatusOK)
+	s.Client().Get(t, articlePath(draft.ID, "/attachments")).ExpectError(t, http.StatusNotFound, "article not found")
+	s.Client().Get(t, "/api/v1/articles/abc/attachments").ExpectError(t, http.StatusBadRequest, "invalid article id")
+	c.Delete(t, articlePath(article.ID, "/attachments/abc")).ExpectError(t, http.StatusBadRequest, "invalid attachment id")
+}
+
+func TestAvatar(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	c := s.Login(t, user)
+	avatarPath := fmt.Sprintf("/api/v1/users/%d/avatar", user.ID)
+
+	s.Client().Get(t, avatarPath).ExpectError(t, http.StatusNotFound, "avatar not found")
+	c.Upload(t, http.MethodPut, "/api/v1/users/me/avatar", "file", "avatar.txt", "image/png", []byte("not an image")).
+		ExpectError(t, http.StatusUnsupportedMediaType, "file type not allowed")
+
+	resp := c.Upload(t, http.MethodPut, "/api/v1/users/me/avatar", "file", "avatar.png", "image/png", pngImage(t, 300)).
+		Expect(t, http.StatusOK).JSON(t)
+	if resp["user"].(map[string]any)["avatar_url"] != avatarPath {
+		t.Fatalf("unexpected upload response: %v", resp)
+	}
+
+	for _, path := range []string{avatarPath, avatarPath + "?size=thumb"} {
+		redirect := s.Client().Get(t, path).Expect(t, http.StatusFound)
+		image := s.Client().Get(t, redirect.Header.Get("Location")).Expect(t, http.StatusOK)
+		if image.Header.Get("Content-Type") != "image/png" {
+			t.Fatalf("unexpected avatar content type %q", image.Header.Get("Content-Type"))
+		}
+	}
+	if me := c.Get(t, "/api/v1/users/me").JSON(t); me["avatar_thumb_url"] != avatarPath+"?size=thumb" {
+		t.Fatalf("expected avatar urls in profile: %v", me)
+	}
+
+	// 声明了超大尺寸的图片在解码前被拒绝
+	c.Upload(t, http.MethodPut, "/api/v1/users/me/avatar", "file", "huge.png", "image/png", oversizedPNG(t, 60000)).
+		ExpectCode(t, http.StatusRequestEntityTooLarge, "image_too_large")
+
+	c.Delete(t, "/api/v1/users/me/avatar").Expect(t, http.StatusOK)
+	s.Client().Get(t, avatarPath).ExpectError(t, http.StatusNotFound, "avatar not found")
+	s.Client().Get(t, "/api/v1/users/999/avatar").ExpectError(t, http.StatusNotFound, "user not found")
+	s.Client().Upload(t, http.MethodPut, "/api/v1/users/me/avatar", "file", "avatar.png", "image/png", pngImage(t, 10)).
+		Expect(t, http.StatusUnauthorized)
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := testutil.NewFakeS3(t)
+	s := testutil.NewServer(t, func(cfg *config.Config) {
+		cfg.Storage.Driver = "s3"
+		cfg.Storage.S3 = config.S3Config{
+			Endpoint:  fake.Endpoint(),
+			Region:    "us-east-1",
+			Bucket:    "uploads",
+			AccessKey: "test-access-key",
+			SecretKey: "test-secret-key",
+		}
+	})
+	ctx := t.Context()
+
+	content := "stored in s3"
+	if err := s.Store.Put(ctx, "docs/readme.txt", strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if data, contentType, ok := fake.Object("uploads", "docs/readme.txt"); !ok || string(data) != content || contentType != "text/plain" {
+		t.Fatalf("unexpected stored object: %q %q %v", data, contentType, ok)
+	}
+
+	r, info, err := s.Store.Get(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(data) != content || info.Size != int64(len(content)) || info.ContentType != "text/plain" {
+		t.Fatalf("unexpected object: %q %+v %v", data, info, err)
+	}
+
+	// 下载地址为对象存储的预签名地址
+	signed, err := s.Store.SignedURL(ctx, "docs/readme.txt", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != fake.Endpoint() || u.Path != "/uploads/docs/readme.txt" ||
+		u.Query().Get("X-Amz-Expires") != "300" || u.Query().Get("X-Amz-Signature") == "" {
+		t.Fatalf("unexpected signed url: %s", signed)
+	}
+	resp, err := http.Get(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(data) != content {
+		t.Fatalf("unexpected download: %q", data)
+	}
+
+	if err := s.Store.Delete(ctx, "docs/readme.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Store.Get(ctx, "docs/readme.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if _, _, err := s.Store.Get(ctx, "../escape"); !errors.Is(err, storage.ErrInvalidKey) {
+		t.Fatalf("expected invalid key, got %v", err)
+	}
+
+	// 通过接口上传的附件同样保存在对象存储中
+	author := s.CreateUser(t)
+	article := s.CreateArticle(t, author)
+	var created struct {
+		Attachment domain.Attachment `json:"attachment"`
+	}
+	s.Login(t, author).Upload(t, http.MethodPost, articlePath(article.ID, "/attachments"), "file", "notes.txt", "text/plain", []byte("attachment notes")).
+		Expect(t, http.StatusCreated).Decode(t, &created)
+	if !strings.HasPrefix(created.Attachment.URL, fake.URL+"/uploads/") {
+		t.Fatalf("expected presigned attachment url, got %q", created.Attachment.URL)
+	}
+	u, err = url.Parse(created.Attachment.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _, ok := fake.Object("uploads", strings.TrimPrefix(u.Path, "/uploads/")); !ok || string(data) != "attachment notes" {
+		t.Fatalf("attachment not stored in s3: %+v", created.Attachment)
+	}
+}
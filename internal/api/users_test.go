@@ -0,0 +1,238 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/testutil"
+)
+
+func TestRegister(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	var created struct {
+		User struct {
+			ID       uint   `json:"id"`
+			Username string `json:"username"`
+			Email    string `json:"email"`
+		} `json:"user"`
+	}
+	c.Post(t, "/api/v1/users/register", map[string]string{
+		"username":  "alice",
+		"email":     "alice@example.com",
+		"password":  "secret123",
+		"full_name": "Alice",
+		"role":      domain.RoleAdmin,
+	}).Expect(t, http.StatusCreated).Decode(t, &created)
+	if created.User.ID == 0 || created.User.Username != "alice" {
+		t.Fatalf("unexpected user: %+v", created.User)
+	}
+
+	// 注册时不能指定角色
+	user, err := s.Users.GetUserByID(context.Background(), created.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != domain.RoleUser {
+		t.Fatalf("expected role %q, got %q", domain.RoleUser, user.Role)
+	}
+
+	// 注册时提交的密码可以用于登录
+	c.Post(t, "/api/v1/users/login", map[string]string{
+		"email":    "alice@example.com",
+		"password": "secret123",
+	}).Expect(t, http.StatusOK)
+}
+
+func TestRegisterErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.CreateUser(t, testutil.WithUsername("taken"))
+	c := s.Client()
+
+	tests := []struct {
+		name   string
+		body   map[string]string
+		status int
+		error  string
+	}{
+		{"missing password", map[string]string{"username": "bob", "email": "bob@example.com"}, http.StatusBadRequest, ""},
+		{"short password", map[string]string{"username": "bob", "email": "bob@example.com", "password": "123"}, http.StatusBadRequest, ""},
+		{"short username", map[string]string{"username": "bo", "email": "bob@example.com", "password": "secret123"}, http.StatusBadRequest, ""},
+		{"invalid email", map[string]string{"username": "bob", "email": "bob", "password": "secret123"}, http.StatusBadRequest, ""},
+		{"reserved username", map[string]string{"username": domain.DeletedUserPrefix + "1", "email": "bob@example.com", "password": "secret123"}, http.StatusBadRequest, "username is reserved"},
+		{"duplicate username", map[string]string{"username": "taken", "email": "bob@example.com", "password": "secret123"}, http.StatusConflict, "username already exists"},
+		{"duplicate email", map[string]string{"username": "bob", "email": "taken@example.com", "password": "secret123"}, http.StatusConflict, "email already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := c.Post(t, "/api/v1/users/register", tt.body).Expect(t, tt.status)
+			if tt.error != "" {
+				resp.ExpectError(t, tt.status, tt.error)
+			}
+		})
+	}
+
+	c.Do(t, http.MethodPost, "/api/v1/users/register", nil, nil).Expect(t, http.StatusBadRequest)
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	const n = 8
+	statuses := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses <- c.Post(t, "/api/v1/users/register", map[string]string{
+				"username": "racer",
+				"email":    fmt.Sprintf("racer%d@example.com", i),
+				"password": "secret123",
+			}).StatusCode
+		}(i)
+	}
+	wg.Wait()
+	close(statuses)
+
+	// 同名注册只有一个成功，其余返回冲突而不是服务器错误
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != n-1 {
+		t.Fatalf("unexpected statuses: %v", counts)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+
+	// 请求上下文取消后，查询不再发往数据库
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Users.GetUserByID(ctx, alice.ID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// 查询超时只作用于单条语句，同一上下文中的后续查询不受影响
+	ctx = context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := s.Users.GetUserByID(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLogin(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	c := s.Client()
+
+	resp := c.Post(t, "/api/v1/users/login", map[string]string{
+		"email":    user.Email,
+		"password": user.Password,
+	}).Expect(t, http.StatusOK).JSON(t)
+	if resp["token"] == "" {
+		t.Fatal("expected token")
+	}
+
+	c.Post(t, "/api/v1/users/login", map[string]string{
+		"email":    user.Email,
+		"password": "wrong-password",
+	}).ExpectError(t, http.StatusUnauthorized, "invalid email or password")
+	c.Post(t, "/api/v1/users/login", map[string]string{
+		"email":    "nobody@example.com",
+		"password": "password123",
+	}).ExpectError(t, http.StatusUnauthorized, "invalid email or password")
+	c.Post(t, "/api/v1/users/login", map[string]string{"email": "not-an-email"}).Expect(t, http.StatusBadRequest)
+}
+
+func TestAuthRequired(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	c.Get(t, "/api/v1/users/me").ExpectError(t, http.StatusUnauthorized, "authorization header is required")
+
+	resp := c.Do(t, http.MethodGet, "/api/v1/users/me", nil, http.Header{"Authorization": {"Token abc"}})
+	resp.ExpectError(t, http.StatusUnauthorized, "authorization header format must be Bearer {token}")
+
+	c.Token = "invalid"
+	c.Get(t, "/api/v1/users/me").ExpectError(t, http.StatusUnauthorized, "invalid or expired token")
+
+	// 公开接口携带无效令牌时按未登录处理
+	c.Get(t, "/api/v1/articles").Expect(t, http.StatusOK)
+}
+
+func TestCurrentUser(t *testing.T) {
+	s := testutil.NewServer(t)
+	user := s.CreateUser(t)
+	c := s.Login(t, user)
+
+	me := c.Get(t, "/api/v1/users/me").Expect(t, http.StatusOK).JSON(t)
+	if me["username"] != user.Username || me["email"] != user.Email || me["followers_count"] != float64(0) {
+		t.Fatalf("unexpected profile: %v", me)
+	}
+
+	c.Put(t, "/api/v1/users/me", map[string]string{"email": "invalid"}).Expect(t, http.StatusBadRequest)
+	updated := c.Put(t, "/api/v1/users/me", map[string]string{
+		"full_name": "New Name",
+		"email":     "new@example.com",
+	}).Expect(t, http.StatusOK).JSON(t)["user"].(map[string]any)
+	if updated["full_name"] != "New Name" || updated["email"] != "new@example.com" {
+		t.Fatalf("unexpected update result: %v", updated)
+	}
+
+	c.Delete(t, "/api/v1/users/me").Expect(t, http.StatusOK)
+	c.Get(t, "/api/v1/users/me").ExpectError(t, http.StatusNotFound, "user not found")
+	s.Client().Get(t, fmt.Sprintf("/api/v1/users/%d", user.ID)).ExpectError(t, http.StatusNotFound, "user not found")
+}
+
+func TestFollow(t *testing.T) {
+	s := testutil.NewServer(t)
+	alice := s.CreateUser(t)
+	bob := s.CreateUser(t)
+	c := s.Login(t, alice)
+
+	bobPath := fmt.Sprintf("/api/v1/users/%d", bob.ID)
+	c.Post(t, bobPath+"/follow", nil).Expect(t, http.StatusOK)
+	// 重复关注是幂等的
+	c.Post(t, bobPath+"/follow", nil).Expect(t, http.StatusOK)
+
+	profile := c.Get(t, bobPath).Expect(t, http.StatusOK).JSON(t)
+	if profile["followers_count"] != float64(1) || profile["followed_by_me"] != true {
+		t.Fatalf("unexpected profile: %v", profile)
+	}
+	if _, ok := s.Client().Get(t, bobPath).JSON(t)["followed_by_me"]; ok {
+		t.Fatal("anonymous profile should not contain followed_by_me")
+	}
+
+	followers := c.Get(t, bobPath+"/followers").Expect(t, http.StatusOK).JSON(t)
+	if followers["total"] != float64(1) {
+		t.Fatalf("unexpected followers: %v", followers)
+	}
+	following := c.Get(t, fmt.Sprintf("/api/v1/users/%d/following", alice.ID)).Expect(t, http.StatusOK).JSON(t)
+	if following["total"] != float64(1) {
+		t.Fatalf("unexpected following: %v", following)
+	}
+
+	c.Delete(t, bobPath+"/follow").Expect(t, http.StatusOK)
+	if n := c.Get(t, bobPath).JSON(t)["followers_count"]; n != float64(0) {
+		t.Fatalf("expected 0 followers, got %v", n)
+	}
+
+	c.Post(t, fmt.Sprintf("/api/v1/users/%d/follow", alice.ID), nil).ExpectError(t, http.StatusBadRequest, "cannot follow yourself")
+	c.Post(t, "/api/v1/users/999/follow", nil).ExpectError(t, http.StatusNotFound, "user not found")
+	c.Post(t, "/api/v1/users/abc/follow", nil).ExpectError(t, http.StatusBadRequest, "invalid user id")
+	c.Get(t, "/api/v1/users/999/followers").ExpectError(t, http.StatusNotFound, "user not found")
+	c.Get(t, "/api/v1/users/999/following").ExpectError(t, http.StatusNotFound, "user not found")
+	c.Get(t, "/api/v1/users/abc").ExpectError(t, http.StatusBadRequest, "invalid user id")
+}
@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/testutil"
+	"github.com/Anning01/user-management/internal/util"
+)
+
+func TestValidationFieldErrors(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	body := map[string]string{"username": "bo", "email": "bob", "password": "secret123"}
+	problem := c.Post(t, "/api/v1/users/register", body).ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	want := []util.FieldError{
+		{Field: "username", Rule: "min", Param: "3", Message: "username must be at least 3 characters in length"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+	}
+	expectFieldErrors(t, problem.Errors, want)
+
+	// 按 Accept-Language 选择语言，不支持的语言回退到英文
+	c.Header = http.Header{"Accept-Language": {"fr-FR, zh-CN;q=0.9, en;q=0.8"}}
+	problem = c.Post(t, "/api/v1/users/register", body).ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	want[0].Message = "username长度必须至少为3个字符"
+	want[1].Message = "email必须是一个有效的邮箱"
+	expectFieldErrors(t, problem.Errors, want)
+
+	c.Header = http.Header{"Accept-Language": {"fr"}}
+	problem = c.Post(t, "/api/v1/users/register", map[string]string{"email": "bob@example.com", "password": "secret123"}).
+		ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	expectFieldErrors(t, problem.Errors, []util.FieldError{
+		{Field: "username", Rule: "required", Message: "username is a required field"},
+	})
+
+	// 非校验错误不返回字段列表
+	problem = c.Get(t, "/api/v1/articles/abc").ExpectCode(t, http.StatusBadRequest, "invalid_article_id")
+	if problem.Errors != nil {
+		t.Fatalf("unexpected field errors: %+v", problem.Errors)
+	}
+}
+
+func TestValidationCustomRules(t *testing.T) {
+	s := testutil.NewServer(t)
+	c := s.Client()
+
+	problem := c.Post(t, "/api/v1/users/register", map[string]string{
+		"username": "bob smith", "email": "bob@example.com", "password": "secret123",
+	}).ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	expectFieldErrors(t, problem.Errors, []util.FieldError{
+		{Field: "username", Rule: "username", Message: "username can only contain letters, numbers, underscores and hyphens"},
+	})
+	c.Post(t, "/api/v1/users/register", map[string]string{
+		"username": "bob_smith-2", "email": "bob@example.com", "password": "secret123",
+	}).Expect(t, http.StatusCreated)
+
+	author := s.Login(t, s.CreateUser(t))
+	author.Header = http.Header{"Accept-Language": {"zh-CN"}}
+	problem = author.Post(t, "/api/v1/articles", map[string]string{
+		"title": `Hello <script>alert(1)</script>`, "content": "Article content here",
+	}).ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	expectFieldErrors(t, problem.Errors, []util.FieldError{
+		{Field: "title", Rule: "nohtml", Message: "title不能包含HTML标签"},
+	})
+	problem = author.Post(t, "/api/v1/series", map[string]string{"title": "<b>Series</b>"}).
+		ExpectCode(t, http.StatusBadRequest, "validation_failed")
+	expectFieldErrors(t, problem.Errors, []util.FieldError{
+		{Field: "title", Rule: "nohtml", Message: "title不能包含HTML标签"},
+	})
+
+	// 比较符号不是标签
+	author.Post(t, "/api/v1/articles", map[string]string{
+		"title": "Why 1 < 2 and 3 > 2", "content": "Article content here",
+	}).Expect(t, http.StatusCreated)
+}
+
+func expectFieldErrors(t *testing.T, got, want []util.FieldError) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected field errors %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected field error %+v, got %+v", want[i], got[i])
+		}
+	}
+}
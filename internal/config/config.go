@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -21,12 +22,27 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// QueryTimeout 秒，单条 SQL 的执行时间上限，0 表示不限制。
+	// 超时基于请求上下文计算，客户端断开时查询同样会被取消。
+	QueryTimeout time.Duration
+	// SlowThreshold 毫秒，执行时间超过该值的 SQL 以 warn 级别记录，0 表示不记录慢查询
+	SlowThreshold time.Duration
 }
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Search   SearchConfig
+	Views    ViewsConfig
+	Feed     FeedConfig
+	Storage  StorageConfig
+	Upload   UploadConfig
+	Trash    TrashConfig
+	Privacy  PrivacyConfig
+	I18n     I18nConfig
+	Log      LogConfig
+	Metrics  MetricsConfig
 }
 
 type JWTConfig struct {
@@ -34,12 +50,123 @@ type JWTConfig struct {
 	ExpirationHours int
 }
 
+type SearchConfig struct {
+	Backend string // memory 或 mysql
+}
+
+type ViewsConfig struct {
+	FlushInterval time.Duration // 秒
+	DedupWindow   time.Duration // 分钟
+}
+
+type FeedConfig struct {
+	BaseURL     string // 订阅源中链接使用的站点地址，为空时根据请求推断
+	Title       string
+	Description string
+	ItemCount   int // 1~100，与文章列表的分页上限一致
+}
+
+type StorageConfig struct {
+	Driver     string        // local 或 s3
+	LocalDir   string        // 本地存储目录
+	PublicURL  string        // 本地存储下载地址前缀，为空时使用相对路径
+	SigningKey string        // 下载地址签名密钥，为空时每次启动随机生成
+	URLExpiry  time.Duration // 分钟，下载地址有效期
+	S3         S3Config
+}
+
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+type UploadConfig struct {
+	MaxSize         int64 // MB，附件大小上限
+	AllowedTypes    []string
+	AvatarMaxSize   int64 // MB，头像大小上限
+	AvatarThumbSize int   // 像素，头像缩略图边长
+	ImportMaxSize   int64 // MB，文章批量导入文件大小上限
+}
+
+type TrashConfig struct {
+	RetentionDays int           // 回收站保留天数，0 表示不自动清理
+	PurgeInterval time.Duration // 小时，清理任务执行间隔
+}
+
+type PrivacyConfig struct {
+	CoolingOffDays int           // 注销冷静期天数
+	ExportExpiry   time.Duration // 小时，导出文件保留时间
+	CheckInterval  time.Duration // 分钟，检查到期注销请求与过期导出的间隔
+}
+
+type I18nConfig struct {
+	DefaultLocale string // 无法协商出语言时使用的语言
+	Dir           string // 自定义消息目录所在目录，其中的 <locale>.json 覆盖或扩展内置文案
+}
+
+type LogConfig struct {
+	Level  string // debug / info / warn / error，debug 级别会记录全部 SQL
+	Format string // text 或 json
+	Access AccessLogConfig
+}
+
+// MetricsConfig Prometheus 指标
+type MetricsConfig struct {
+	Enabled bool // 默认关闭，/metrics 不需要认证
+	// Port 不为空时在该端口单独提供 /metrics（管理端口，不对公网开放），为空时由 API 服务器提供
+	Port string
+}
+
+// AccessLogConfig 访问日志，每个请求结束时记录一条 request 日志
+type AccessLogConfig struct {
+	Enabled bool
+	// SampleRate 0~1，状态码小于 500 的请求按该比例抽样记录，服务端错误总是记录
+	SampleRate float64
+	// SkipPaths 不记录访问日志的路径，例如健康检查
+	SkipPaths []string
+}
+
 func Load() (*Config, error) {
 	// 1. 设置默认值
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.readTimeout", 5)
 	viper.SetDefault("server.writeTimeout", 10)
 	viper.SetDefault("server.maxHeaderBytes", 1<<20)
+	viper.SetDefault("database.driver", "mysql")
+	viper.SetDefault("database.sslMode", "disable")
+	viper.SetDefault("database.queryTimeout", 10)
+	viper.SetDefault("database.slowThreshold", 200)
+	viper.SetDefault("search.backend", "memory")
+	viper.SetDefault("views.flushInterval", 10)
+	viper.SetDefault("views.dedupWindow", 30)
+	viper.SetDefault("feed.title", "User Management")
+	viper.SetDefault("feed.description", "Latest articles")
+	viper.SetDefault("feed.itemCount", 20)
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.localDir", "./uploads")
+	viper.SetDefault("storage.urlExpiry", 60)
+	viper.SetDefault("storage.s3.useSSL", true)
+	viper.SetDefault("upload.maxSize", 10)
+	viper.SetDefault("upload.allowedTypes", []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf", "text/plain"})
+	viper.SetDefault("upload.avatarMaxSize", 5)
+	viper.SetDefault("upload.avatarThumbSize", 128)
+	viper.SetDefault("upload.importMaxSize", 50)
+	viper.SetDefault("trash.retentionDays", 30)
+	viper.SetDefault("trash.purgeInterval", 24)
+	viper.SetDefault("privacy.coolingOffDays", 14)
+	viper.SetDefault("privacy.exportExpiry", 72)
+	viper.SetDefault("privacy.checkInterval", 60)
+	viper.SetDefault("i18n.defaultLocale", "en")
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.access.enabled", true)
+	viper.SetDefault("log.access.sampleRate", 1)
+	viper.SetDefault("log.access.skipPaths", []string{"/health"})
+	viper.SetDefault("metrics.enabled", false)
 
 	// 2. 先绑定环境变量（必须在读取配置文件之前）
 	// 手动绑定环境变量，支持 DB_PASSWORD 这种格式
@@ -49,9 +176,29 @@ func Load() (*Config, error) {
 	viper.BindEnv("database.port", "DB_PORT")
 	viper.BindEnv("database.name", "DB_NAME")
 	viper.BindEnv("database.driver", "DB_DRIVER")
+	viper.BindEnv("database.sslMode", "DB_SSL_MODE")
+	viper.BindEnv("database.queryTimeout", "DB_QUERY_TIMEOUT")
+	viper.BindEnv("database.slowThreshold", "DB_SLOW_THRESHOLD")
 	viper.BindEnv("jwt.secretKey", "JWT_SECRET_KEY")
 	viper.BindEnv("jwt.expirationHours", "JWT_EXPIRATION_HOURS")
 	viper.BindEnv("server.port", "SERVER_PORT")
+	viper.BindEnv("search.backend", "SEARCH_BACKEND")
+	viper.BindEnv("feed.baseURL", "FEED_BASE_URL")
+	viper.BindEnv("storage.driver", "STORAGE_DRIVER")
+	viper.BindEnv("storage.signingKey", "STORAGE_SIGNING_KEY")
+	viper.BindEnv("storage.s3.endpoint", "S3_ENDPOINT")
+	viper.BindEnv("storage.s3.bucket", "S3_BUCKET")
+	viper.BindEnv("storage.s3.accessKey", "S3_ACCESS_KEY")
+	viper.BindEnv("storage.s3.secretKey", "S3_SECRET_KEY")
+	viper.BindEnv("i18n.defaultLocale", "I18N_DEFAULT_LOCALE")
+	viper.BindEnv("i18n.dir", "I18N_DIR")
+	viper.BindEnv("log.level", "LOG_LEVEL")
+	viper.BindEnv("log.format", "LOG_FORMAT")
+	viper.BindEnv("log.access.enabled", "LOG_ACCESS_ENABLED")
+	viper.BindEnv("log.access.sampleRate", "LOG_ACCESS_SAMPLE_RATE")
+	viper.BindEnv("log.access.skipPaths", "LOG_ACCESS_SKIP_PATHS")
+	viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
+	viper.BindEnv("metrics.port", "METRICS_PORT")
 
 	// 3. 读取配置文件 (config.yaml)
 	viper.SetConfigName("config")
@@ -72,5 +219,30 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// 5. 校验取值，避免后台任务等在运行时才因非法配置出错
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
+
+// Validate 检查配置取值是否合法
+func (c *Config) Validate() error {
+	// 后台定时任务的间隔必须为正数，否则 time.NewTicker 会 panic
+	if c.Views.FlushInterval <= 0 {
+		return fmt.Errorf("views.flushInterval must be positive, got %d", c.Views.FlushInterval)
+	}
+	// 回收站保留期为 0 时不启动清理任务，无需检查间隔
+	if c.Trash.RetentionDays > 0 && c.Trash.PurgeInterval <= 0 {
+		return fmt.Errorf("trash.purgeInterval must be positive, got %d", c.Trash.PurgeInterval)
+	}
+	if c.Privacy.CheckInterval <= 0 {
+		return fmt.Errorf("privacy.checkInterval must be positive, got %d", c.Privacy.CheckInterval)
+	}
+	// 订阅源按文章列表分页查询，超出 1~100 时会被重置为默认的 10 篇
+	if c.Feed.ItemCount < 1 || c.Feed.ItemCount > 100 {
+		return fmt.Errorf("feed.itemCount must be between 1 and 100, got %d", c.Feed.ItemCount)
+	}
+	return nil
+}
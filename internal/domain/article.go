@@ -1,18 +1,120 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
+	"github.com/Anning01/user-management/pkg/listquery"
+	"github.com/Anning01/user-management/pkg/render"
+
 	"gorm.io/gorm"
 )
 
+// 文章状态
+const (
+	ArticleStatusDraft     = "draft"     // 草稿：仅作者与协作者可见
+	ArticleStatusPublished = "published" // 已发布：所有人可见
+)
+
 type Article struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Title     string         `gorm:"size:200;not null" json:"title" validate:"required,min=3,max=200"`
-	Content   string         `gorm:"type:text;not null" json:"content" validate:"required,min=10"`
-	AuthorID  uint           `gorm:"not null" json:"author_id"`
-	Author    User           `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint                 `gorm:"primaryKey;index:idx_articles_created_id,priority:2;index:idx_articles_author_created,priority:3" json:"id"`
+	Title         string               `gorm:"size:200;not null" json:"title" validate:"required,min=3,max=200,nohtml"`
+	Slug          string               `gorm:"size:200;uniqueIndex:idx_articles_slug" json:"slug"`
+	Content       string               `gorm:"type:text;not null" json:"content" validate:"required,min=10"`
+	ContentFormat string               `gorm:"size:20;not null;default:plain" json:"content_format" validate:"omitempty,oneof=plain markdown"`
+	Excerpt       string               `gorm:"size:1000" json:"excerpt"`
+	Status        string               `gorm:"size:20;not null;default:published;index" json:"status" validate:"omitempty,oneof=draft published"`
+	ExternalID    *string              `gorm:"size:100;uniqueIndex:idx_articles_author_external,priority:2" json:"external_id,omitempty"`
+	AuthorID      uint                 `gorm:"not null;index:idx_articles_author_created,priority:1;uniqueIndex:idx_articles_author_external,priority:1" json:"author_id"`
+	Author        User                 `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	CoAuthors     []ArticleContributor `gorm:"foreignKey:ArticleID" json:"co_authors,omitempty"`
+	CommentCount  int64                `gorm:"not null;default:0" json:"comment_count"`
+	LikeCount     int64                `gorm:"not null;default:0" json:"like_count"`
+	ViewCount     int64                `gorm:"not null;default:0" json:"view_count"`
+	CreatedAt     time.Time            `gorm:"index:idx_articles_created_id,priority:1;index:idx_articles_author_created,priority:2" json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt       `gorm:"index" json:"-"`
+
+	// 以下字段针对当前登录用户计算，仅在已认证的请求中返回
+	LikedByMe      *bool `gorm:"-" json:"liked_by_me,omitempty"`
+	BookmarkedByMe *bool `gorm:"-" json:"bookmarked_by_me,omitempty"`
+
+	// 所属系列及上一篇、下一篇，仅在文章详情中返回
+	Series *SeriesNav `gorm:"-" json:"series,omitempty"`
+
+	// 渲染后的正文与目录，仅在请求 render=html 时返回
+	RenderedHTML string           `gorm:"-" json:"rendered_html,omitempty"`
+	TOC          []render.Heading `gorm:"-" json:"toc,omitempty"`
+}
+
+// ArticleSlugRedirect 文章修改 slug 后保留的旧 slug，访问时重定向到当前 slug
+type ArticleSlugRedirect struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"size:200;not null;uniqueIndex" json:"slug"`
+	ArticleID uint      `gorm:"not null;index" json:"article_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArticleSearchResult 文章搜索结果
+type ArticleSearchResult struct {
+	Article    Article           `json:"article"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// ArticleListSchema 文章列表接口允许的排序、过滤与字段
+var ArticleListSchema = &listquery.Schema{
+	Sorts: map[string]string{
+		"id":         "id",
+		"title":      "title",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"like_count": "like_count",
+		"view_count": "view_count",
+	},
+	Filters: map[string]listquery.Filter{
+		"author_id":      {Column: "author_id", Op: "=", Parse: listquery.ParseUint},
+		"status":         {Column: "status", Op: "=", Parse: parseArticleStatus},
+		"created_after":  {Column: "created_at", Op: ">=", Parse: listquery.ParseTime},
+		"created_before": {Column: "created_at", Op: "<", Parse: listquery.ParseTime},
+		"updated_after":  {Column: "updated_at", Op: ">=", Parse: listquery.ParseTime},
+		"updated_before": {Column: "updated_at", Op: "<", Parse: listquery.ParseTime},
+	},
+	Fields: map[string]string{
+		"id":             "id",
+		"title":          "title",
+		"content":        "content",
+		"content_format": "content_format",
+		"excerpt":        "excerpt",
+		"status":         "status",
+		"author_id":      "author_id",
+		"comment_count":  "comment_count",
+		"like_count":     "like_count",
+		"view_count":     "view_count",
+		"created_at":     "created_at",
+		"updated_at":     "updated_at",
+	},
+	Relations: map[string]listquery.Relation{
+		"author": {
+			Preload:    "Author",
+			ForeignKey: "author_id",
+			Fields: map[string]string{
+				"id":        "id",
+				"username":  "username",
+				"full_name": "full_name",
+			},
+		},
+	},
+}
+
+// Published 文章是否已发布
+func (a *Article) Published() bool {
+	return a.Status != ArticleStatusDraft
+}
+
+func parseArticleStatus(s string) (interface{}, error) {
+	if s != ArticleStatusDraft && s != ArticleStatusPublished {
+		return nil, errors.New("invalid article status")
+	}
+	return s, nil
 }
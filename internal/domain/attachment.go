@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Attachment 文章附件，文件内容保存在 pkg/storage 中
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ArticleID   uint      `gorm:"not null;index" json:"article_id"`
+	UploaderID  uint      `gorm:"not null" json:"uploader_id"`
+	StorageKey  string    `gorm:"size:255;not null" json:"-"`
+	FileName    string    `gorm:"size:255;not null" json:"file_name"`
+	ContentType string    `gorm:"size:100;not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// URL 带有效期的下载地址，查询时生成
+	URL string `gorm:"-" json:"url,omitempty"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CommentStatusVisible = "visible"
+	CommentStatusHidden  = "hidden"
+)
+
+type Comment struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ArticleID uint           `gorm:"not null;index" json:"article_id"`
+	AuthorID  uint           `gorm:"not null;index" json:"author_id"`
+	Author    User           `gorm:"foreignKey:AuthorID;-:migration" json:"author,omitempty"`
+	ParentID  *uint          `gorm:"index" json:"parent_id"`
+	Content   string         `gorm:"type:text;not null" json:"content" validate:"required,min=1,max=2000"`
+	Status    string         `gorm:"size:20;not null;default:visible" json:"status"`
+	Flagged   bool           `gorm:"not null;default:false;index" json:"flagged"`
+	FlagCount int            `gorm:"not null;default:0" json:"flag_count"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 以下字段仅用于组装评论树，不存储在数据库中
+	Deleted bool       `gorm:"-" json:"deleted,omitempty"`
+	Replies []*Comment `gorm:"-" json:"replies,omitempty"`
+}
+
+// Visible 评论是否对读者可见（未删除且未被隐藏）
+func (c *Comment) Visible() bool {
+	return !c.DeletedAt.Valid && c.Status == CommentStatusVisible
+}
+
+// CommentFlag 用户对评论的举报记录，每个用户对同一条评论只能举报一次
+type CommentFlag struct {
+	CommentID uint      `gorm:"primaryKey" json:"comment_id"`
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	Reason    string    `gorm:"size:200" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// 文章协作者角色
+const (
+	ContributorRoleOwner  = "owner"  // 所有者：可编辑、删除文章并管理协作者
+	ContributorRoleEditor = "editor" // 编辑：可编辑文章和附件
+	ContributorRoleViewer = "viewer" // 查看者：可查看草稿
+)
+
+// ArticleContributor 文章协作者，文章作者（AuthorID）始终是所有者，无需单独记录
+type ArticleContributor struct {
+	ArticleID uint      `gorm:"primaryKey" json:"article_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role      string    `gorm:"size:20;not null" json:"role"`
+	InvitedBy uint      `gorm:"not null" json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ValidContributorRole 判断是否为合法的协作者角色
+func ValidContributorRole(role string) bool {
+	switch role {
+	case ContributorRoleOwner, ContributorRoleEditor, ContributorRoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanEdit 角色是否可以编辑文章
+func CanEdit(role string) bool {
+	return role == ContributorRoleOwner || role == ContributorRoleEditor
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ArticleLike 用户点赞记录
+type ArticleLike struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	ArticleID uint      `gorm:"primaryKey;index" json:"article_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Bookmark 用户收藏记录
+type Bookmark struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	ArticleID uint      `gorm:"primaryKey;index" json:"article_id"`
+	Article   Article   `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+import "github.com/Anning01/user-management/internal/domain/errs"
+
+// ErrAlreadyExists 写入的数据违反唯一约束，例如并发注册了相同的用户名
+var ErrAlreadyExists = errs.Conflict("already_exists", "already exists")
+
+// 通用
+var (
+	ErrPermissionDenied = errs.Forbidden("permission_denied", "permission denied")
+)
+
+// 用户
+var (
+	ErrUserNotFound          = errs.NotFound("user_not_found", "user not found")
+	ErrUsernameReserved      = errs.Validation("username_reserved", "username is reserved")
+	ErrUsernameExists        = errs.Conflict("username_exists", "username already exists")
+	ErrEmailExists           = errs.Conflict("email_exists", "email already exists")
+	ErrUsernameOrEmailExists = errs.Conflict("username_or_email_exists", "username or email already exists")
+	ErrInvalidCredentials    = errs.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrCannotFollowSelf      = errs.Validation("cannot_follow_self", "cannot follow yourself")
+	ErrAvatarNotFound        = errs.NotFound("avatar_not_found", "avatar not found")
+	ErrInvalidImage          = errs.Validation("invalid_image", "invalid image")
+	ErrImageTooLarge         = errs.TooLarge("image_too_large", "image dimensions too large")
+	ErrUnsupportedLocale     = errs.Validation("unsupported_locale", "unsupported locale")
+)
+
+// 文章
+var (
+	ErrArticleNotFound          = errs.NotFound("article_not_found", "article not found")
+	ErrAuthorNotFound           = errs.NotFound("author_not_found", "author not found")
+	ErrAuthorDeleted            = errs.Conflict("author_deleted", "author is deleted")
+	ErrArticleDeleted           = errs.Conflict("article_deleted", "article is deleted")
+	ErrInvalidSlug              = errs.Validation("invalid_slug", "invalid slug")
+	ErrSlugInUse                = errs.Conflict("slug_in_use", "slug already in use")
+	ErrInvalidArticleStatus     = errs.Validation("invalid_article_status", "invalid article status")
+	ErrUnsupportedContentFormat = errs.Validation("unsupported_content_format", "unsupported content format")
+)
+
+// 协作者
+var (
+	ErrContributorNotFound    = errs.NotFound("contributor_not_found", "contributor not found")
+	ErrInvalidContributorRole = errs.Validation("invalid_contributor_role", "invalid contributor role")
+	ErrCannotChangeAuthorRole = errs.Validation("cannot_change_author_role", "cannot change the author's role")
+	ErrCannotRemoveAuthor     = errs.Validation("cannot_remove_author", "cannot remove the author")
+)
+
+// 系列
+var (
+	ErrSeriesNotFound         = errs.NotFound("series_not_found", "series not found")
+	ErrArticleNotInSeries     = errs.NotFound("article_not_in_series", "article not in series")
+	ErrArticleInSeries        = errs.Conflict("article_in_series", "article already in a series")
+	ErrSeriesArticlesMismatch = errs.Validation("series_articles_mismatch", "article ids do not match series")
+)
+
+// 评论
+var (
+	ErrCommentNotFound       = errs.NotFound("comment_not_found", "comment not found")
+	ErrParentCommentNotFound = errs.Validation("parent_comment_not_found", "parent comment not found")
+)
+
+// 上传
+var (
+	ErrFileTooLarge       = errs.TooLarge("file_too_large", "file too large")
+	ErrFileTypeNotAllowed = errs.Unsupported("file_type_not_allowed", "file type not allowed")
+	ErrAttachmentNotFound = errs.NotFound("attachment_not_found", "attachment not found")
+)
+
+// 导入导出
+var (
+	ErrUnsupportedTransferFormat = errs.Validation("unsupported_transfer_format", "unsupported transfer format")
+	ErrInvalidZipFile            = errs.Validation("invalid_zip_file", "invalid zip file")
+	ErrAuthorIDRequired          = errs.Validation("author_id_required", "author_id is required")
+	ErrDuplicateExternalID       = errs.Validation("duplicate_external_id", "duplicate external_id")
+)
+
+// 个人数据导出与账号注销
+var (
+	ErrExportNotFound   = errs.NotFound("export_not_found", "export not found")
+	ErrExportInProgress = errs.Conflict("export_in_progress", "export already in progress")
+	ErrInvalidPassword  = errs.Forbidden("invalid_password", "invalid password")
+	ErrErasureRequested = errs.Conflict("erasure_requested", "erasure already requested")
+	ErrErasureNotFound  = errs.NotFound("erasure_not_found", "erasure request not found")
+)
@@ -0,0 +1,140 @@
+// Package errs 定义带类别的领域错误。
+// 服务层返回这里定义的错误，API 层据类别统一映射 HTTP 状态码，不再比较错误文本。
+package errs
+
+import "errors"
+
+// Kind 错误类别
+type Kind uint8
+
+const (
+	KindInternal     Kind = iota // 未预期的错误
+	KindValidation               // 请求参数不合法
+	KindUnauthorized             // 未登录或凭证无效
+	KindForbidden                // 已登录但无权操作
+	KindNotFound                 // 资源不存在或不可见
+	KindConflict                 // 与现有数据冲突
+	KindTooLarge                 // 请求内容超过大小限制
+	KindUnsupported              // 不支持的内容类型
+)
+
+// Error 领域错误
+// Code 为稳定的错误码，供客户端判断错误类型，不随提示文案变化；Message 为英文描述。
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Key 提示文案在消息目录中的键，为空时使用 Code；Params 替换文案中 {name} 形式的占位符
+	Key    string
+	Params map[string]string
+	// Err 原始错误，只用于日志，不返回给客户端
+	Err error
+
+	// generic 为 true 时表示类别哨兵，errors.Is 只比较类别
+	generic bool
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 错误码相同的错误视为同一错误；与类别哨兵比较时只比较类别
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.generic {
+		return t.Kind == e.Kind
+	}
+	return t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap 返回附带原始错误的副本，错误码与描述不变
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	wrapped.generic = false
+	return &wrapped
+}
+
+// WithMessage 返回替换描述后的副本，用于同一错误码下给出更具体的说明
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := *e
+	wrapped.Message = message
+	wrapped.generic = false
+	return &wrapped
+}
+
+// WithKey 返回使用指定文案键与占位参数的副本，用于同一错误码下需要不同提示文案的场景，
+// Message 仍需与英文文案保持一致
+func (e *Error) WithKey(key string, params map[string]string) *Error {
+	wrapped := *e
+	wrapped.Key = key
+	wrapped.Params = params
+	wrapped.generic = false
+	return &wrapped
+}
+
+// 类别哨兵，用于 errors.Is(err, errs.ErrNotFound) 判断错误类别，也可直接返回
+var (
+	ErrValidation   = &Error{Kind: KindValidation, Code: "validation_failed", Message: "validation failed", generic: true}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized, Code: "unauthorized", Message: "unauthorized", generic: true}
+	ErrForbidden    = &Error{Kind: KindForbidden, Code: "forbidden", Message: "forbidden", generic: true}
+	ErrNotFound     = &Error{Kind: KindNotFound, Code: "not_found", Message: "not found", generic: true}
+	ErrConflict     = &Error{Kind: KindConflict, Code: "conflict", Message: "conflict", generic: true}
+	ErrTooLarge     = &Error{Kind: KindTooLarge, Code: "too_large", Message: "content too large", generic: true}
+	ErrUnsupported  = &Error{Kind: KindUnsupported, Code: "unsupported_media_type", Message: "unsupported media type", generic: true}
+)
+
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func TooLarge(code, message string) *Error {
+	return &Error{Kind: KindTooLarge, Code: code, Message: message}
+}
+
+func Unsupported(code, message string) *Error {
+	return &Error{Kind: KindUnsupported, Code: code, Message: message}
+}
+
+// As 返回错误链中的领域错误，不存在时返回 nil
+func As(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+// KindOf 返回错误类别，非领域错误视为 KindInternal
+func KindOf(err error) Kind {
+	if e := As(err); e != nil {
+		return e.Kind
+	}
+	return KindInternal
+}
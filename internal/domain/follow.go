@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Follow 关注关系：FollowerID 关注了 FolloweeID
+type Follow struct {
+	FollowerID uint      `gorm:"primaryKey" json:"follower_id"`
+	FolloweeID uint      `gorm:"primaryKey;index" json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
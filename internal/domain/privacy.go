@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// 数据导出状态
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusReady      = "ready"
+	ExportStatusFailed     = "failed"
+)
+
+// 账号注销请求状态
+const (
+	ErasureStatusPending   = "pending"
+	ErasureStatusCompleted = "completed"
+	ErasureStatusCancelled = "cancelled"
+)
+
+// DeletedUserPrefix 注销并保留文章的用户被匿名化为 deleted-user-<id>，该前缀不允许注册
+const DeletedUserPrefix = "deleted-user-"
+
+// DataExport 个人数据导出任务，生成的压缩包保存在 pkg/storage 中
+type DataExport struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	StorageKey  string     `gorm:"size:255" json:"-"`
+	Size        int64      `json:"size"`
+	Error       string     `gorm:"size:500" json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"`
+
+	// URL 带有效期的下载地址，导出完成后生成
+	URL string `gorm:"-" json:"url,omitempty"`
+}
+
+// ErasureRequest 账号注销请求，冷静期结束后执行
+type ErasureRequest struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	KeepArticles bool       `gorm:"not null;default:false" json:"keep_articles"`
+	Status       string     `gorm:"size:20;not null;index" json:"status"`
+	ScheduledAt  time.Time  `gorm:"index" json:"scheduled_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// PersonalData 导出的个人数据
+type PersonalData struct {
+	ExportedAt  time.Time     `json:"exported_at"`
+	Profile     User          `json:"profile"`
+	Articles    []Article     `json:"articles"`
+	Comments    []Comment     `json:"comments"`
+	Likes       []ArticleLike `json:"likes"`
+	Bookmarks   []Bookmark    `json:"bookmarks"`
+	Following   []Follow      `json:"following"`
+	Followers   []Follow      `json:"followers"`
+	Attachments []Attachment  `json:"attachments"`
+}
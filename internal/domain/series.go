@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Series 系列：作者创建的有序文章合集，如多篇连载的教程
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"size:200;not null" json:"title" validate:"required,min=3,max=200,nohtml"`
+	Description string    `gorm:"size:1000" json:"description" validate:"max=1000"`
+	AuthorID    uint      `gorm:"not null;index" json:"author_id"`
+	Author      User      `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Articles 按顺序排列的文章，仅在查询系列详情时返回
+	Articles []Article `gorm:"-" json:"articles,omitempty"`
+}
+
+// SeriesArticle 系列中的一篇文章，每篇文章最多属于一个系列
+// Position 从 1 开始，决定文章在系列中的顺序。
+type SeriesArticle struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false" json:"article_id"`
+	SeriesID  uint      `gorm:"not null;index:idx_series_articles_position,priority:1" json:"series_id"`
+	Position  int       `gorm:"not null;index:idx_series_articles_position,priority:2" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SeriesNav 文章详情中的系列信息与上一篇、下一篇导航
+type SeriesNav struct {
+	ID       uint        `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Previous *ArticleRef `json:"previous"`
+	Next     *ArticleRef `json:"next"`
+}
+
+// ArticleRef 文章的简要引用
+type ArticleRef struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
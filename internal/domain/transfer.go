@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// 文章批量导入导出的格式
+const (
+	TransferFormatJSONL    = "jsonl"    // 每行一篇文章的 JSON
+	TransferFormatMarkdown = "markdown" // zip 压缩包，每篇文章一个带 YAML front matter 的 Markdown 文件
+)
+
+// ArticleRecord 导入导出时一篇文章的表示
+// Markdown 格式中 Content 为文件正文，其余字段写在 front matter 中。
+type ArticleRecord struct {
+	ExternalID    string     `json:"external_id" yaml:"external_id" validate:"required,max=100"`
+	ID            uint       `json:"id,omitempty" yaml:"id,omitempty"`
+	AuthorID      uint       `json:"author_id,omitempty" yaml:"author_id,omitempty"`
+	Title         string     `json:"title" yaml:"title" validate:"required,min=3,max=200,nohtml"`
+	Slug          string     `json:"slug,omitempty" yaml:"slug,omitempty" validate:"omitempty,max=80"`
+	ContentFormat string     `json:"content_format,omitempty" yaml:"content_format,omitempty" validate:"omitempty,oneof=plain markdown"`
+	Status        string     `json:"status,omitempty" yaml:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	Content       string     `json:"content" yaml:"-" validate:"required,min=10"`
+	CreatedAt     *time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" yaml:"deleted_at,omitempty"`
+}
+
+// NewArticleRecord 由文章生成导出记录
+// 未设置外部标识的文章以 slug 作为 external_id，重新导入时据此匹配原文章。
+func NewArticleRecord(article *Article) ArticleRecord {
+	record := ArticleRecord{
+		ExternalID:    article.Slug,
+		ID:            article.ID,
+		AuthorID:      article.AuthorID,
+		Title:         article.Title,
+		Slug:          article.Slug,
+		ContentFormat: article.ContentFormat,
+		Status:        article.Status,
+		Content:       article.Content,
+		CreatedAt:     &article.CreatedAt,
+		UpdatedAt:     &article.UpdatedAt,
+	}
+	if article.ExternalID != nil {
+		record.ExternalID = *article.ExternalID
+	}
+	if article.DeletedAt.Valid {
+		record.DeletedAt = &article.DeletedAt.Time
+	}
+	return record
+}
+
+// ImportReport 批量导入结果
+type ImportReport struct {
+	DryRun    bool          `json:"dry_run"`
+	Total     int           `json:"total"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Failed    int           `json:"failed"`
+	Errors    []ImportError `json:"errors"`
+}
+
+// ImportError 导入失败的行，Row 从 1 开始；Markdown 格式中 File 为压缩包内的文件名
+type ImportError struct {
+	Row        int    `json:"row"`
+	File       string `json:"file,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+	// Code 领域错误的错误码，例如 author_not_found、validation_failed，解析失败等其他错误为空
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error"`
+	// Err 原始错误，接口据此按请求语言翻译 Error
+	Err error `json:"-"`
+}
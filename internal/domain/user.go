@@ -6,14 +6,30 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Username  string         `gorm:"size:50;uniqueIndex;not null" json:"username" validate:"required,min=3,max=50"`
-	Email     string         `gorm:"size:100;uniqueIndex;not null" json:"email" validate:"required,email"`
-	Password  string         `gorm:"size:100;not null" json:"-" validate:"required,min=6"`
-	FullName  string         `gorm:"size:100" json:"full_name"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Articles  []Article      `gorm:"foreignKey:AuthorID" json:"articles,omitempty"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Username string `gorm:"size:50;uniqueIndex;not null" json:"username" validate:"required,min=3,max=50,username"`
+	Email    string `gorm:"size:100;uniqueIndex;not null" json:"email" validate:"required,email"`
+	Password string `gorm:"size:100;not null" json:"-" validate:"required,min=6"`
+	FullName string `gorm:"size:100" json:"full_name"`
+	Role     string `gorm:"size:20;not null;default:user" json:"role"`
+	// Locale 接口提示文案的语言偏好，如 zh-CN、en，为空时按 Accept-Language 协商
+	Locale string `gorm:"size:20" json:"locale"`
+	// 头像原图与缩略图在存储中的 key，通过 /users/:id/avatar 访问
+	AvatarKey      string         `gorm:"size:255" json:"-"`
+	AvatarThumbKey string         `gorm:"size:255" json:"-"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	Articles       []Article      `gorm:"foreignKey:AuthorID" json:"articles,omitempty"`
+}
+
+// IsAdmin 是否为管理员
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
 }
@@ -0,0 +1,222 @@
+// Package i18n 提供接口提示文案的多语言消息目录与语言协商。
+// 内置中文（zh-CN）与英文（en）目录，可通过目录中的 <locale>.json 文件覆盖已有文案或增加新语言。
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fallback 目录中缺少文案时最终使用的语言，内置目录保证包含全部文案
+const Fallback = "en"
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+// Bundle 消息目录，创建后只读，可并发使用
+type Bundle struct {
+	defaultLocale string
+	// messages 语言 -> 文案键 -> 文案
+	messages map[string]map[string]string
+}
+
+// NewBundle 加载内置目录，dir 不为空时再加载其中的 <locale>.json 文件，
+// 同名文案覆盖内置文案。defaultLocale 为无法协商出语言时使用的语言，必须存在于目录中。
+func NewBundle(defaultLocale, dir string) (*Bundle, error) {
+	b := &Bundle{messages: make(map[string]map[string]string)}
+	if err := b.load(builtin, "locales"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := b.load(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+
+	if defaultLocale == "" {
+		defaultLocale = Fallback
+	}
+	locale, ok := b.Canonical(defaultLocale)
+	if !ok {
+		return nil, fmt.Errorf("i18n: default locale %q has no message catalog", defaultLocale)
+	}
+	b.defaultLocale = locale
+	return b, nil
+}
+
+// load 读取 dir 下的全部 JSON 目录文件，文件名（去掉扩展名）即语言标签
+func (b *Bundle) load(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("i18n: parse %s: %w", file, err)
+		}
+
+		locale := strings.TrimSuffix(path.Base(file), ".json")
+		if existing, ok := b.Canonical(locale); ok {
+			locale = existing
+		}
+		if b.messages[locale] == nil {
+			b.messages[locale] = make(map[string]string, len(messages))
+		}
+		for key, text := range messages {
+			b.messages[locale][key] = text
+		}
+	}
+	return nil
+}
+
+// DefaultLocale 默认语言
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Locales 目录中的全部语言，按字母顺序排列
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Canonical 返回与 tag 对应的目录语言，比较时忽略大小写并把 _ 视为 -
+func (b *Bundle) Canonical(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	for locale := range b.messages {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Match 依次尝试候选语言标签，返回第一个能匹配的目录语言：先精确匹配，
+// 再匹配主语言相同的语言（例如 zh 与 zh-TW 匹配 zh-CN）。都不匹配时返回默认语言。
+func (b *Bundle) Match(tags ...string) string {
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if locale, ok := b.Canonical(tag); ok {
+			return locale
+		}
+		primary := primaryTag(tag)
+		for _, locale := range b.Locales() {
+			if strings.EqualFold(primaryTag(locale), primary) {
+				return locale
+			}
+		}
+	}
+	return b.defaultLocale
+}
+
+// Localizer 返回指定语言的本地化器，locale 不在目录中时使用默认语言
+func (b *Bundle) Localizer(locale string) *Localizer {
+	if canonical, ok := b.Canonical(locale); ok {
+		return &Localizer{bundle: b, locale: canonical}
+	}
+	return &Localizer{bundle: b, locale: b.defaultLocale}
+}
+
+// Localizer 以确定的语言翻译文案
+type Localizer struct {
+	bundle *Bundle
+	locale string
+}
+
+// Locale 使用的语言
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// Bundle 所属的消息目录
+func (l *Localizer) Bundle() *Bundle {
+	return l.bundle
+}
+
+// Lookup 查找文案并替换 {name} 形式的占位符，当前语言缺少该文案时依次回退到默认语言与英文
+func (l *Localizer) Lookup(key string, params map[string]string) (string, bool) {
+	for _, locale := range []string{l.locale, l.bundle.defaultLocale, Fallback} {
+		if text, ok := l.bundle.messages[locale][key]; ok {
+			return format(text, params), true
+		}
+	}
+	return "", false
+}
+
+// T 翻译文案，目录中不存在时返回 key 本身
+func (l *Localizer) T(key string, params map[string]string) string {
+	if text, ok := l.Lookup(key, params); ok {
+		return text
+	}
+	return key
+}
+
+func format(text string, params map[string]string) string {
+	if len(params) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func primaryTag(tag string) string {
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return strings.ToLower(primary)
+}
+
+// ParseAcceptLanguage 按权重从高到低返回 Accept-Language 中的语言标签，
+// 权重相同的保持原有顺序，q=0 与通配符 * 被忽略。
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag, q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+// Package metrics 以 Prometheus 文本格式暴露服务指标：HTTP 请求、数据库连接池、登录与注册、Go 运行时。
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 登录结果
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+// Metrics 服务指标，使用独立的注册表，同一进程中可以创建多个（例如测试）。
+// 方法允许 nil 接收者，未启用指标时传入 nil 即可，不需要额外判断。
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	logins        *prometheus.CounterVec
+	registrations prometheus.Counter
+}
+
+// New 创建指标并注册 Go 运行时与进程指标
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds by method, route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "user_logins_total",
+			Help: "Total number of login attempts by result.",
+		}, []string{"result"}),
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "user_registrations_total",
+			Help: "Total number of successful user registrations.",
+		}),
+	}
+	// 预先创建两种登录结果，尚未发生时也输出 0
+	m.logins.WithLabelValues(LoginSuccess)
+	m.logins.WithLabelValues(LoginFailure)
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.logins,
+		m.registrations,
+	)
+	return m
+}
+
+// RegisterDB 注册数据库连接池指标（go_sql_*），数据来自 sqlDB.Stats()，name 用于区分多个连接池
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	if m == nil {
+		return nil
+	}
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler 输出 Prometheus 文本格式的指标
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest 记录一次 HTTP 请求，route 为路由模板，避免路径参数造成标签数量膨胀
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// Login 记录一次登录尝试
+func (m *Metrics) Login(success bool) {
+	if m == nil {
+		return
+	}
+	result := LoginFailure
+	if success {
+		result = LoginSuccess
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
+
+// Registered 记录一次成功注册
+func (m *Metrics) Registered() {
+	if m == nil {
+		return
+	}
+	m.registrations.Inc()
+}
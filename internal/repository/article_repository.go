@@ -1,20 +1,55 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/pkg/listquery"
+	"github.com/Anning01/user-management/pkg/pagination"
 
 	"gorm.io/gorm"
 )
 
 type ArticleRepository interface {
-	Create(article *domain.Article) error
-	FindByID(id uint) (*domain.Article, error)
-	FindAll(limit, offset int) ([]domain.Article, int64, error)
-	FindByAuthorID(authorID uint, limit, offset int) ([]domain.Article, int64, error)
-	Update(article *domain.Article) error
-	Delete(id uint) error
+	Create(ctx context.Context, article *domain.Article) error
+	FindByID(ctx context.Context, id uint) (*domain.Article, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]domain.Article, error)
+	FindBySlug(ctx context.Context, slug string) (*domain.Article, error)
+	FindByExternalID(ctx context.Context, authorID uint, externalID string) (*domain.Article, error)
+	FindAfterID(ctx context.Context, authorID, afterID uint, limit int) ([]domain.Article, error)
+	ImportBatch(ctx context.Context, items []ImportItem, dryRun bool) ([]error, error)
+	FindSlugRedirect(ctx context.Context, slug string) (*domain.ArticleSlugRedirect, error)
+	SlugTaken(ctx context.Context, slug string, articleID uint) (bool, error)
+	RetireSlug(ctx context.Context, articleID uint, oldSlug, newSlug string) error
+	FindAll(ctx context.Context, limit, offset int, q *listquery.Query) ([]domain.Article, int64, error)
+	FindByAuthorID(ctx context.Context, authorID uint, includeDrafts bool, limit, offset int, q *listquery.Query) ([]domain.Article, int64, error)
+	FindAllByCursor(ctx context.Context, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, error)
+	FindByAuthorIDByCursor(ctx context.Context, authorID uint, includeDrafts bool, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, error)
+	FindFeedByCursor(ctx context.Context, followerID uint, page pagination.CursorPage) ([]domain.Article, error)
+	Update(ctx context.Context, article *domain.Article) error
+	Delete(ctx context.Context, id uint) error
+	DeleteByAuthor(ctx context.Context, authorID uint, deletedAt time.Time) ([]uint, error)
+	FindDeleted(ctx context.Context, authorID uint, limit, offset int) ([]domain.Article, int64, error)
+	FindDeletedByID(ctx context.Context, id uint) (*domain.Article, error)
+	Restore(ctx context.Context, id uint) error
+	UpdateCommentCount(ctx context.Context, id uint, delta int) error
+}
+
+// ImportItem 批量导入的一篇文章，ID 为 0 时新建，否则更新
+// OldSlug 为更新前的 slug，与新 slug 不同时保留为重定向。
+type ImportItem struct {
+	Article *domain.Article
+	OldSlug string
 }
 
+// errDryRun 试运行时用于回滚事务
+var errDryRun = errors.New("dry run")
+
+// articleCounterColumns 由计数操作单独维护的列，普通更新时不覆盖
+var articleCounterColumns = []string{"comment_count", "like_count", "view_count"}
+
 type articleRepository struct {
 	db *gorm.DB
 }
@@ -23,54 +58,326 @@ func NewArticleRepository(db *gorm.DB) ArticleRepository {
 	return &articleRepository{db}
 }
 
-func (r *articleRepository) Create(article *domain.Article) error {
-	return r.db.Create(article).Error
+func (r *articleRepository) Create(ctx context.Context, article *domain.Article) error {
+	return translateError(conn(ctx, r.db).Create(article).Error)
 }
 
-func (r *articleRepository) FindByID(id uint) (*domain.Article, error) {
+func (r *articleRepository) FindByID(ctx context.Context, id uint) (*domain.Article, error) {
 	var article domain.Article
-	if err := r.db.Preload("Author").First(&article, id).Error; err != nil {
+	if err := conn(ctx, r.db).Preload("Author").Scopes(withCoAuthors).First(&article, id).Error; err != nil {
 		return nil, err
 	}
 	return &article, nil
 }
 
-func (r *articleRepository) FindAll(limit, offset int) ([]domain.Article, int64, error) {
+func (r *articleRepository) FindByIDs(ctx context.Context, ids []uint) ([]domain.Article, error) {
+	var articles []domain.Article
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	if err := conn(ctx, r.db).Preload("Author").Scopes(withCoAuthors).Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (r *articleRepository) FindBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	var article domain.Article
+	if err := conn(ctx, r.db).Preload("Author").Scopes(withCoAuthors).Where("slug = ?", slug).First(&article).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FindByExternalID 按外部标识查询文章，包括回收站中的文章
+func (r *articleRepository) FindByExternalID(ctx context.Context, authorID uint, externalID string) (*domain.Article, error) {
+	var article domain.Article
+	if err := conn(ctx, r.db).Unscoped().Where("author_id = ? AND external_id = ?", authorID, externalID).
+		First(&article).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FindAfterID 按 ID 顺序分批读取文章，authorID 为 0 时读取全部作者
+func (r *articleRepository) FindAfterID(ctx context.Context, authorID, afterID uint, limit int) ([]domain.Article, error) {
+	var articles []domain.Article
+	query := conn(ctx, r.db).Where("id > ?", afterID)
+	if authorID != 0 {
+		query = query.Where("author_id = ?", authorID)
+	}
+	if err := query.Order("id").Limit(limit).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// ImportBatch 在一个事务中写入一批文章，返回每篇文章的错误
+// 每篇文章使用独立的保存点，单篇失败不影响同批其他文章；dryRun 为 true 时最终回滚。
+func (r *articleRepository) ImportBatch(ctx context.Context, items []ImportItem, dryRun bool) ([]error, error) {
+	errs := make([]error, len(items))
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for i, item := range items {
+			errs[i] = translateError(tx.Transaction(func(tx *gorm.DB) error {
+				return importArticle(tx, item)
+			}))
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return errs, nil
+}
+
+func importArticle(tx *gorm.DB, item ImportItem) error {
+	article := item.Article
+	if article.ID == 0 {
+		return tx.Create(article).Error
+	}
+
+	if err := tx.Model(article).Select("title", "slug", "content", "content_format", "excerpt", "status", "external_id").
+		Updates(article).Error; err != nil {
+		return err
+	}
+	if article.Slug == item.OldSlug {
+		return nil
+	}
+	if err := tx.Where("slug = ? AND article_id = ?", article.Slug, article.ID).
+		Delete(&domain.ArticleSlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if item.OldSlug == "" {
+		return nil
+	}
+	return tx.Create(&domain.ArticleSlugRedirect{Slug: item.OldSlug, ArticleID: article.ID}).Error
+}
+
+func (r *articleRepository) FindSlugRedirect(ctx context.Context, slug string) (*domain.ArticleSlugRedirect, error) {
+	var redirect domain.ArticleSlugRedirect
+	if err := conn(ctx, r.db).Where("slug = ?", slug).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// SlugTaken 判断 slug 是否已被其他文章（包括已删除的文章和旧 slug）占用
+// articleID 为 0 表示新文章。
+func (r *articleRepository) SlugTaken(ctx context.Context, slug string, articleID uint) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Unscoped().Model(&domain.Article{}).
+		Where("slug = ? AND id <> ?", slug, articleID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := conn(ctx, r.db).Model(&domain.ArticleSlugRedirect{}).
+		Where("slug = ? AND article_id <> ?", slug, articleID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RetireSlug 文章 slug 变更后保留旧 slug 作为重定向
+// 如果新 slug 是该文章以前用过的旧 slug，则删除对应的重定向记录。
+func (r *articleRepository) RetireSlug(ctx context.Context, articleID uint, oldSlug, newSlug string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ? AND article_id = ?", newSlug, articleID).
+			Delete(&domain.ArticleSlugRedirect{}).Error; err != nil {
+			return err
+		}
+		if oldSlug == "" {
+			return nil
+		}
+		return tx.Create(&domain.ArticleSlugRedirect{Slug: oldSlug, ArticleID: articleID}).Error
+	})
+}
+
+func (r *articleRepository) FindAll(ctx context.Context, limit, offset int, q *listquery.Query) ([]domain.Article, int64, error) {
 	var articles []domain.Article
 	var total int64
 
-	if err := r.db.Model(&domain.Article{}).Count(&total).Error; err != nil {
+	query := conn(ctx, r.db).Model(&domain.Article{}).Scopes(published, q.Filter)
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.Preload("Author").Limit(limit).Offset(offset).Order("created_at desc").Find(&articles).Error; err != nil {
+	if err := query.Scopes(withAuthor(q), q.Select("created_at"), orderBy(q)).Limit(limit).Offset(offset).Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return articles, total, nil
 }
 
-func (r *articleRepository) FindByAuthorID(authorID uint, limit, offset int) ([]domain.Article, int64, error) {
+// FindByAuthorID 查询作者的文章，includeDrafts 为 false 时只返回已发布的文章
+func (r *articleRepository) FindByAuthorID(ctx context.Context, authorID uint, includeDrafts bool, limit, offset int, q *listquery.Query) ([]domain.Article, int64, error) {
 	var articles []domain.Article
 	var total int64
 
-	query := r.db.Model(&domain.Article{}).Where("author_id = ?", authorID)
+	query := conn(ctx, r.db).Model(&domain.Article{}).Where("author_id = ?", authorID).Scopes(publishedUnless(includeDrafts), q.Filter)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := query.Limit(limit).Offset(offset).Order("created_at desc").Find(&articles).Error; err != nil {
+	if err := query.Scopes(q.Select("created_at"), orderBy(q)).Limit(limit).Offset(offset).Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return articles, total, nil
 }
 
-func (r *articleRepository) Update(article *domain.Article) error {
-	return r.db.Save(article).Error
+func (r *articleRepository) FindAllByCursor(ctx context.Context, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, error) {
+	return findByCursor(conn(ctx, r.db).Scopes(published, q.Filter, withAuthor(q), q.Select("created_at")), page)
+}
+
+func (r *articleRepository) FindByAuthorIDByCursor(ctx context.Context, authorID uint, includeDrafts bool, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, error) {
+	return findByCursor(conn(ctx, r.db).Where("author_id = ?", authorID).Scopes(publishedUnless(includeDrafts), q.Filter, q.Select("created_at")), page)
+}
+
+// FindFeedByCursor 查询 followerID 关注的作者发布的文章（读时扇出）
+// 每个作者的文章通过 (author_id, created_at, id) 索引按时间倒序读取。
+func (r *articleRepository) FindFeedByCursor(ctx context.Context, followerID uint, page pagination.CursorPage) ([]domain.Article, error) {
+	followees := conn(ctx, r.db).Model(&domain.Follow{}).Select("followee_id").Where("follower_id = ?", followerID)
+	return findByCursor(conn(ctx, r.db).Preload("Author").Scopes(published).Where("author_id IN (?)", followees), page)
+}
+
+// published 只查询已发布的文章
+func published(db *gorm.DB) *gorm.DB {
+	return db.Where("articles.status = ?", domain.ArticleStatusPublished)
+}
+
+func publishedUnless(includeDrafts bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if includeDrafts {
+			return db
+		}
+		return published(db)
+	}
+}
+
+// withCoAuthors 预加载署名的协作者（所有者与编辑），查看者不对外展示
+func withCoAuthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("CoAuthors", func(db *gorm.DB) *gorm.DB {
+		return db.Where("role IN ?", []string{domain.ContributorRoleOwner, domain.ContributorRoleEditor}).
+			Order("created_at, user_id")
+	}).Preload("CoAuthors.User")
+}
+
+// withAuthor 未指定字段选择时预加载完整的作者信息
+func withAuthor(q *listquery.Query) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q.HasFields() {
+			return db
+		}
+		return db.Preload("Author").Scopes(withCoAuthors)
+	}
+}
+
+// orderBy 使用请求指定的排序，默认按创建时间倒序
+func orderBy(q *listquery.Query) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q.HasSort() {
+			return q.Sort(db)
+		}
+		return db.Order("created_at desc, id desc")
+	}
+}
+
+// findByCursor 基于 (created_at, id) 的键集分页查询，多取一条用于判断是否还有更多数据
+// 结果总是按 created_at、id 倒序返回。
+func findByCursor(query *gorm.DB, page pagination.CursorPage) ([]domain.Article, error) {
+	var articles []domain.Article
+
+	switch {
+	case page.After != nil:
+		c := page.After
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", c.CreatedAt, c.CreatedAt, c.ID).
+			Order("created_at desc, id desc")
+	case page.Before != nil:
+		c := page.Before
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", c.CreatedAt, c.CreatedAt, c.ID).
+			Order("created_at asc, id asc")
+	default:
+		query = query.Order("created_at desc, id desc")
+	}
+
+	if err := query.Limit(page.Limit + 1).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	if page.Before != nil {
+		for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+			articles[i], articles[j] = articles[j], articles[i]
+		}
+	}
+	return articles, nil
+}
+
+func (r *articleRepository) Update(ctx context.Context, article *domain.Article) error {
+	return translateError(conn(ctx, r.db).Omit(articleCounterColumns...).Save(article).Error)
+}
+
+func (r *articleRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Article{}, id).Error
+}
+
+// DeleteByAuthor 以指定的删除时间软删除作者的全部文章，返回被删除的文章ID
+// 与用户使用相同的删除时间，恢复用户时据此只恢复随用户一起删除的文章。
+func (r *articleRepository) DeleteByAuthor(ctx context.Context, authorID uint, deletedAt time.Time) ([]uint, error) {
+	db := conn(ctx, r.db)
+	var ids []uint
+	if err := db.Model(&domain.Article{}).Where("author_id = ?", authorID).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	if err := db.Model(&domain.Article{}).Where("id IN ?", ids).Update("deleted_at", deletedAt).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindDeleted 查询回收站中的文章，authorID 为 0 时查询全部作者，按删除时间倒序
+func (r *articleRepository) FindDeleted(ctx context.Context, authorID uint, limit, offset int) ([]domain.Article, int64, error) {
+	var articles []domain.Article
+	var total int64
+
+	query := conn(ctx, r.db).Unscoped().Model(&domain.Article{}).Where("deleted_at IS NOT NULL")
+	if authorID != 0 {
+		query = query.Where("author_id = ?", authorID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Author", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Order("deleted_at desc").Limit(limit).Offset(offset).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+func (r *articleRepository) FindDeletedByID(ctx context.Context, id uint) (*domain.Article, error) {
+	var article domain.Article
+	if err := conn(ctx, r.db).Unscoped().Where("deleted_at IS NOT NULL").First(&article, id).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+func (r *articleRepository) Restore(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Model(&domain.Article{}).Where("id = ?", id).Update("deleted_at", nil).Error
 }
 
-func (r *articleRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Article{}, id).Error
+func (r *articleRepository) UpdateCommentCount(ctx context.Context, id uint, delta int) error {
+	return conn(ctx, r.db).Model(&domain.Article{}).Where("id = ?", id).
+		UpdateColumn("comment_count", gorm.Expr("comment_count + ?", delta)).Error
 }
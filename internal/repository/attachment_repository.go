@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	FindByID(ctx context.Context, id uint) (*domain.Attachment, error)
+	FindByArticleID(ctx context.Context, articleID uint) ([]domain.Attachment, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	return conn(ctx, r.db).Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := conn(ctx, r.db).First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) FindByArticleID(ctx context.Context, articleID uint) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	if err := conn(ctx, r.db).Where("article_id = ?", articleID).Order("id").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Attachment{}, id).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.Comment) error
+	FindByID(ctx context.Context, id uint) (*domain.Comment, error)
+	FindThreadByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error)
+	FindFlagged(ctx context.Context, limit, offset int) ([]domain.Comment, int64, error)
+	UpdateContent(ctx context.Context, comment *domain.Comment) error
+	UpdateModeration(ctx context.Context, comment *domain.Comment) error
+	Delete(ctx context.Context, id uint) error
+	AddFlag(ctx context.Context, flag *domain.CommentFlag) (bool, error)
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db}
+}
+
+// preloadCommentAuthor 只加载评论作者的公开信息
+func preloadCommentAuthor(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped().Select("id", "username", "full_name")
+	})
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	return conn(ctx, r.db).Create(comment).Error
+}
+
+func (r *commentRepository) FindByID(ctx context.Context, id uint) (*domain.Comment, error) {
+	var comment domain.Comment
+	if err := conn(ctx, r.db).Scopes(preloadCommentAuthor).First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindThreadByArticleID 查询文章下的全部评论（包含已删除的评论，用于保留楼层结构）
+func (r *commentRepository) FindThreadByArticleID(ctx context.Context, articleID uint) ([]*domain.Comment, error) {
+	var comments []*domain.Comment
+	if err := conn(ctx, r.db).Unscoped().Scopes(preloadCommentAuthor).
+		Where("article_id = ?", articleID).
+		Order("created_at asc, id asc").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) FindFlagged(ctx context.Context, limit, offset int) ([]domain.Comment, int64, error) {
+	var comments []domain.Comment
+	var total int64
+
+	query := conn(ctx, r.db).Model(&domain.Comment{}).Where("flagged = ?", true)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Scopes(preloadCommentAuthor).Limit(limit).Offset(offset).Order("flag_count desc, created_at asc").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// UpdateContent 只更新评论内容，避免用读取时的旧值覆盖并发写入的审核状态与举报计数
+func (r *commentRepository) UpdateContent(ctx context.Context, comment *domain.Comment) error {
+	return conn(ctx, r.db).Model(comment).Select("content", "updated_at").Updates(comment).Error
+}
+
+// UpdateModeration 只更新审核状态与是否在审核队列中
+func (r *commentRepository) UpdateModeration(ctx context.Context, comment *domain.Comment) error {
+	return conn(ctx, r.db).Model(comment).Select("status", "flagged", "updated_at").Updates(comment).Error
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Comment{}, id).Error
+}
+
+// AddFlag 记录一次举报并累加举报次数，重复举报返回 false
+func (r *commentRepository) AddFlag(ctx context.Context, flag *domain.CommentFlag) (bool, error) {
+	added := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(flag)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(&domain.Comment{}).Where("id = ?", flag.CommentID).
+			UpdateColumns(map[string]interface{}{
+				"flag_count": gorm.Expr("flag_count + ?", 1),
+				"flagged":    true,
+			}).Error
+	})
+	return added, err
+}
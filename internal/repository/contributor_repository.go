@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContributorRepository interface {
+	Find(ctx context.Context, articleID, userID uint) (*domain.ArticleContributor, error)
+	FindByArticleID(ctx context.Context, articleID uint) ([]domain.ArticleContributor, error)
+	FindArticlesByUserID(ctx context.Context, userID uint, limit, offset int) ([]domain.Article, int64, error)
+	Save(ctx context.Context, contributor *domain.ArticleContributor) error
+	Delete(ctx context.Context, articleID, userID uint) error
+}
+
+type contributorRepository struct {
+	db *gorm.DB
+}
+
+func NewContributorRepository(db *gorm.DB) ContributorRepository {
+	return &contributorRepository{db}
+}
+
+func (r *contributorRepository) Find(ctx context.Context, articleID, userID uint) (*domain.ArticleContributor, error) {
+	var contributor domain.ArticleContributor
+	if err := conn(ctx, r.db).Where("article_id = ? AND user_id = ?", articleID, userID).First(&contributor).Error; err != nil {
+		return nil, err
+	}
+	return &contributor, nil
+}
+
+// FindByArticleID 查询文章的全部协作者，按加入时间排序
+func (r *contributorRepository) FindByArticleID(ctx context.Context, articleID uint) ([]domain.ArticleContributor, error) {
+	var contributors []domain.ArticleContributor
+	if err := conn(ctx, r.db).Preload("User").Where("article_id = ?", articleID).
+		Order("created_at, user_id").Find(&contributors).Error; err != nil {
+		return nil, err
+	}
+	return contributors, nil
+}
+
+// FindArticlesByUserID 查询 userID 作为协作者参与的文章（包括草稿），按加入时间倒序
+func (r *contributorRepository) FindArticlesByUserID(ctx context.Context, userID uint, limit, offset int) ([]domain.Article, int64, error) {
+	var articles []domain.Article
+	var total int64
+
+	query := conn(ctx, r.db).Model(&domain.Article{}).
+		Joins("JOIN article_contributors ON article_contributors.article_id = articles.id").
+		Where("article_contributors.user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Select("articles.*").Scopes(withCoAuthors).Preload("Author").Limit(limit).Offset(offset).
+		Order("article_contributors.created_at desc, articles.id desc").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// Save 添加协作者，已存在时更新角色
+func (r *contributorRepository) Save(ctx context.Context, contributor *domain.ArticleContributor) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "updated_at"}),
+	}).Create(contributor).Error
+}
+
+func (r *contributorRepository) Delete(ctx context.Context, articleID, userID uint) error {
+	return conn(ctx, r.db).Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&domain.ArticleContributor{}).Error
+}
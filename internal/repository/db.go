@@ -2,35 +2,99 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/pkg/logger"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// sqliteMemory 使用内存数据库时 database.name 的取值
+const sqliteMemory = ":memory:"
+
 func NewDBConnection(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	fmt.Println(cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.NewGormLogger(cfg.SlowThreshold * time.Millisecond),
+		// 将各驱动的唯一约束冲突等错误统一为 gorm.ErrDuplicatedKey 等通用错误
+		TranslateError: true,
+		// 时间统一以 UTC 保存，SQLite 以文本比较时间，混用时区会导致游标分页的比较出错
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.QueryTimeout > 0 {
+		if err := registerQueryTimeout(db, cfg.QueryTimeout*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
 	// 设置连接池
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	if dialector.Name() == DriverSQLite {
+		// SQLite 同一时间只允许一个写入者，单连接可以避免 database is locked 错误，
+		// 内存数据库也只有在同一连接上才能看到相同的数据
+		sqlDB.SetMaxOpenConns(1)
+		return db, nil
+	}
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
 	return db, nil
 }
+
+// newDialector 根据 database.driver 生成对应的 GORM 方言，未设置时默认为 MySQL
+func newDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+		return mysql.Open(dsn), nil
+	case DriverPostgres, "postgresql":
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Name, sslMode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite, "sqlite3":
+		return sqlite.Open(sqliteDSN(cfg.Name)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+}
+
+// sqliteDSN database.name 为数据库文件路径，:memory: 表示内存数据库
+// 开启外键约束以与 MySQL、PostgreSQL 的行为保持一致。
+func sqliteDSN(name string) string {
+	pragmas := "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if name == "" || name == sqliteMemory {
+		return "file::memory:?" + pragmas
+	}
+	return "file:" + name + "?" + pragmas + "&_pragma=journal_mode(WAL)"
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EngagementRepository interface {
+	AddLike(ctx context.Context, userID, articleID uint) error
+	RemoveLike(ctx context.Context, userID, articleID uint) error
+	AddBookmark(ctx context.Context, userID, articleID uint) error
+	RemoveBookmark(ctx context.Context, userID, articleID uint) error
+	FindBookmarkedArticles(ctx context.Context, userID uint, limit, offset int) ([]domain.Article, int64, error)
+	FindLikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) (map[uint]bool, error)
+	FindBookmarkedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) (map[uint]bool, error)
+	IncrementViewCounts(ctx context.Context, counts map[uint]int64) error
+}
+
+type engagementRepository struct {
+	db *gorm.DB
+}
+
+func NewEngagementRepository(db *gorm.DB) EngagementRepository {
+	return &engagementRepository{db}
+}
+
+// AddLike 点赞，重复点赞不会重复计数
+func (r *engagementRepository) AddLike(ctx context.Context, userID, articleID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.ArticleLike{UserID: userID, ArticleID: articleID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&domain.Article{}).Where("id = ?", articleID).
+			UpdateColumn("like_count", gorm.Expr("like_count + ?", 1)).Error
+	})
+}
+
+// RemoveLike 取消点赞，未点赞时不做任何操作
+func (r *engagementRepository) RemoveLike(ctx context.Context, userID, articleID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&domain.ArticleLike{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&domain.Article{}).Where("id = ? AND like_count > 0", articleID).
+			UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error
+	})
+}
+
+func (r *engagementRepository) AddBookmark(ctx context.Context, userID, articleID uint) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.Bookmark{UserID: userID, ArticleID: articleID}).Error
+}
+
+func (r *engagementRepository) RemoveBookmark(ctx context.Context, userID, articleID uint) error {
+	return conn(ctx, r.db).Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&domain.Bookmark{}).Error
+}
+
+// FindBookmarkedArticles 按收藏时间倒序查询用户收藏的文章，已转为草稿的文章不返回
+func (r *engagementRepository) FindBookmarkedArticles(ctx context.Context, userID uint, limit, offset int) ([]domain.Article, int64, error) {
+	var articles []domain.Article
+	var total int64
+
+	query := conn(ctx, r.db).Model(&domain.Article{}).
+		Joins("JOIN bookmarks ON bookmarks.article_id = articles.id").
+		Where("bookmarks.user_id = ?", userID).
+		Scopes(published)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Author").Limit(limit).Offset(offset).
+		Order("bookmarks.created_at desc").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+func (r *engagementRepository) FindLikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) (map[uint]bool, error) {
+	return r.findArticleIDs(ctx, &domain.ArticleLike{}, userID, articleIDs)
+}
+
+func (r *engagementRepository) FindBookmarkedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) (map[uint]bool, error) {
+	return r.findArticleIDs(ctx, &domain.Bookmark{}, userID, articleIDs)
+}
+
+func (r *engagementRepository) findArticleIDs(ctx context.Context, model interface{}, userID uint, articleIDs []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+
+	var ids []uint
+	if err := conn(ctx, r.db).Model(model).Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Pluck("article_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// IncrementViewCounts 在一个事务中批量累加文章浏览量
+func (r *engagementRepository) IncrementViewCounts(ctx context.Context, counts map[uint]int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for articleID, n := range counts {
+			if err := tx.Model(&domain.Article{}).Where("id = ?", articleID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository interface {
+	Create(ctx context.Context, follow *domain.Follow) error
+	Delete(ctx context.Context, followerID, followeeID uint) error
+	Exists(ctx context.Context, followerID, followeeID uint) (bool, error)
+	FindFollowers(ctx context.Context, userID uint, limit, offset int) ([]domain.User, int64, error)
+	FindFollowing(ctx context.Context, userID uint, limit, offset int) ([]domain.User, int64, error)
+	CountFollowers(ctx context.Context, userID uint) (int64, error)
+	CountFollowing(ctx context.Context, userID uint) (int64, error)
+}
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{db}
+}
+
+// Create 创建关注关系，已关注时不做任何操作
+func (r *followRepository) Create(ctx context.Context, follow *domain.Follow) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error
+}
+
+func (r *followRepository) Delete(ctx context.Context, followerID, followeeID uint) error {
+	return conn(ctx, r.db).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&domain.Follow{}).Error
+}
+
+func (r *followRepository) Exists(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&domain.Follow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindFollowers 查询关注了 userID 的用户，按关注时间倒序
+func (r *followRepository) FindFollowers(ctx context.Context, userID uint, limit, offset int) ([]domain.User, int64, error) {
+	return r.findUsers(ctx, "follows.follower_id", "follows.followee_id", userID, limit, offset)
+}
+
+// FindFollowing 查询 userID 关注的用户，按关注时间倒序
+func (r *followRepository) FindFollowing(ctx context.Context, userID uint, limit, offset int) ([]domain.User, int64, error) {
+	return r.findUsers(ctx, "follows.followee_id", "follows.follower_id", userID, limit, offset)
+}
+
+func (r *followRepository) findUsers(ctx context.Context, joinColumn, whereColumn string, userID uint, limit, offset int) ([]domain.User, int64, error) {
+	var users []domain.User
+	var total int64
+
+	query := conn(ctx, r.db).Model(&domain.User{}).
+		Joins("JOIN follows ON "+joinColumn+" = users.id").
+		Where(whereColumn+" = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Limit(limit).Offset(offset).Order("follows.created_at desc").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *followRepository) CountFollowers(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&domain.Follow{}).
+		Joins("JOIN users ON users.id = follows.follower_id AND users.deleted_at IS NULL").
+		Where("follows.followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *followRepository) CountFollowing(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&domain.Follow{}).
+		Joins("JOIN users ON users.id = follows.followee_id AND users.deleted_at IS NULL").
+		Where("follows.follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository 个人数据导出与账号注销
+type PrivacyRepository interface {
+	CreateExport(ctx context.Context, export *domain.DataExport) error
+	FindExportByID(ctx context.Context, id uint) (*domain.DataExport, error)
+	FindExportsByUserID(ctx context.Context, userID uint) ([]domain.DataExport, error)
+	FindExportsByStatus(ctx context.Context, statuses ...string) ([]domain.DataExport, error)
+	UpdateExport(ctx context.Context, export *domain.DataExport) error
+	DeleteExpiredExports(ctx context.Context, now time.Time) ([]string, error)
+	CollectUserData(ctx context.Context, userID uint) (*domain.PersonalData, error)
+
+	CreateErasureRequest(ctx context.Context, request *domain.ErasureRequest) error
+	FindPendingErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error)
+	FindDueErasures(ctx context.Context, now time.Time) ([]domain.ErasureRequest, error)
+	UpdateErasureRequest(ctx context.Context, request *domain.ErasureRequest) error
+	EraseUser(ctx context.Context, userID uint, keepArticles bool) (*PurgeResult, error)
+}
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{db}
+}
+
+func (r *privacyRepository) CreateExport(ctx context.Context, export *domain.DataExport) error {
+	return conn(ctx, r.db).Create(export).Error
+}
+
+func (r *privacyRepository) FindExportByID(ctx context.Context, id uint) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := conn(ctx, r.db).First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *privacyRepository) FindExportsByUserID(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("id desc").Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *privacyRepository) FindExportsByStatus(ctx context.Context, statuses ...string) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	if err := conn(ctx, r.db).Where("status IN ?", statuses).Order("id").Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *privacyRepository) UpdateExport(ctx context.Context, export *domain.DataExport) error {
+	return conn(ctx, r.db).Save(export).Error
+}
+
+// DeleteExpiredExports 删除已过期的导出记录，返回需要删除的文件
+func (r *privacyRepository) DeleteExpiredExports(ctx context.Context, now time.Time) ([]string, error) {
+	var keys []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Where("expires_at IS NOT NULL AND expires_at < ?", now.UTC())
+		if err := expired.Model(&domain.DataExport{}).Where("storage_key <> ''").
+			Pluck("storage_key", &keys).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at IS NOT NULL AND expires_at < ?", now.UTC()).Delete(&domain.DataExport{}).Error
+	})
+	return keys, err
+}
+
+// CollectUserData 读取用户的全部个人数据（包括回收站中的文章）
+func (r *privacyRepository) CollectUserData(ctx context.Context, userID uint) (*domain.PersonalData, error) {
+	data := &domain.PersonalData{ExportedAt: time.Now()}
+
+	if err := conn(ctx, r.db).Unscoped().First(&data.Profile, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Unscoped().Where("author_id = ?", userID).Order("id").Find(&data.Articles).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Where("author_id = ?", userID).Order("id").Find(&data.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at").Find(&data.Likes).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Preload("Article").Where("user_id = ?", userID).Order("created_at").Find(&data.Bookmarks).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Where("follower_id = ?", userID).Order("created_at").Find(&data.Following).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Where("followee_id = ?", userID).Order("created_at").Find(&data.Followers).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Where("uploader_id = ?", userID).Order("id").Find(&data.Attachments).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *privacyRepository) CreateErasureRequest(ctx context.Context, request *domain.ErasureRequest) error {
+	return conn(ctx, r.db).Create(request).Error
+}
+
+func (r *privacyRepository) FindPendingErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error) {
+	var request domain.ErasureRequest
+	if err := conn(ctx, r.db).Where("user_id = ? AND status = ?", userID, domain.ErasureStatusPending).
+		First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *privacyRepository) FindDueErasures(ctx context.Context, now time.Time) ([]domain.ErasureRequest, error) {
+	var requests []domain.ErasureRequest
+	if err := conn(ctx, r.db).Where("status = ? AND scheduled_at <= ?", domain.ErasureStatusPending, now.UTC()).
+		Order("scheduled_at").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *privacyRepository) UpdateErasureRequest(ctx context.Context, request *domain.ErasureRequest) error {
+	return conn(ctx, r.db).Save(request).Error
+}
+
+// EraseUser 注销用户并清除其个人数据
+// keepArticles 为 true 时匿名化用户信息，未删除的文章和评论保留在匿名账号下；
+// 否则用户连同全部文章彻底删除，评论按 purgeComments 删除或清空内容。回收站中的文章总是彻底删除。
+func (r *privacyRepository) EraseUser(ctx context.Context, userID uint, keepArticles bool) (*PurgeResult, error) {
+	result := &PurgeResult{UserIDs: []uint{userID}}
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Unscoped().First(&user, userID).Error; err != nil {
+			return err
+		}
+		collectAvatarKeys(result, &user)
+
+		articles := tx.Unscoped().Model(&domain.Article{}).Where("author_id = ?", userID)
+		if keepArticles {
+			articles = articles.Where("deleted_at IS NOT NULL")
+		}
+		if err := articles.Pluck("id", &result.ArticleIDs).Error; err != nil {
+			return err
+		}
+		if err := purgeArticles(tx, result); err != nil {
+			return err
+		}
+		if err := purgeUserData(tx, result.UserIDs, result, keepArticles); err != nil {
+			return err
+		}
+
+		if !keepArticles {
+			if err := purgeSeries(tx, result.UserIDs); err != nil {
+				return err
+			}
+			return tx.Unscoped().Delete(&domain.User{}, userID).Error
+		}
+		placeholder := fmt.Sprintf("%s%d", domain.DeletedUserPrefix, userID)
+		return tx.Unscoped().Model(&user).Updates(map[string]interface{}{
+			"username":         placeholder,
+			"email":            placeholder + "@deleted.invalid",
+			"password":         "!", // 不是有效的 bcrypt 哈希，无法再登录
+			"full_name":        "",
+			"role":             domain.RoleUser,
+			"avatar_key":       "",
+			"avatar_thumb_key": "",
+		}).Error
+	})
+	return result, err
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type SeriesRepository interface {
+	Create(ctx context.Context, series *domain.Series) error
+	FindByID(ctx context.Context, id uint) (*domain.Series, error)
+	FindByAuthorID(ctx context.Context, authorID uint, limit, offset int) ([]domain.Series, int64, error)
+	Update(ctx context.Context, series *domain.Series) error
+	Delete(ctx context.Context, id uint) error
+	FindArticles(ctx context.Context, seriesID uint, includeDrafts bool) ([]domain.Article, error)
+	FindEntry(ctx context.Context, articleID uint) (*domain.SeriesArticle, error)
+	AddArticle(ctx context.Context, seriesID, articleID uint) (*domain.SeriesArticle, error)
+	RemoveArticle(ctx context.Context, seriesID, articleID uint) error
+	Reorder(ctx context.Context, seriesID uint, articleIDs []uint) error
+}
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db}
+}
+
+func (r *seriesRepository) Create(ctx context.Context, series *domain.Series) error {
+	return conn(ctx, r.db).Create(series).Error
+}
+
+func (r *seriesRepository) FindByID(ctx context.Context, id uint) (*domain.Series, error) {
+	var series domain.Series
+	if err := conn(ctx, r.db).Preload("Author").First(&series, id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindByAuthorID 查询作者的系列，按更新时间倒序
+func (r *seriesRepository) FindByAuthorID(ctx context.Context, authorID uint, limit, offset int) ([]domain.Series, int64, error) {
+	var series []domain.Series
+	var total int64
+
+	query := conn(ctx, r.db).Model(&domain.Series{}).Where("author_id = ?", authorID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Author").Order("updated_at desc, id desc").Limit(limit).Offset(offset).Find(&series).Error; err != nil {
+		return nil, 0, err
+	}
+	return series, total, nil
+}
+
+func (r *seriesRepository) Update(ctx context.Context, series *domain.Series) error {
+	return conn(ctx, r.db).Model(series).Select("title", "description").Updates(series).Error
+}
+
+// Delete 删除系列，系列中的文章本身保留
+func (r *seriesRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&domain.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Series{}, id).Error
+	})
+}
+
+// FindArticles 按顺序查询系列中的文章，已删除的文章不返回；includeDrafts 为 false 时只返回已发布的文章
+func (r *seriesRepository) FindArticles(ctx context.Context, seriesID uint, includeDrafts bool) ([]domain.Article, error) {
+	var articles []domain.Article
+	if err := conn(ctx, r.db).Model(&domain.Article{}).Select("articles.*").
+		Joins("JOIN series_articles ON series_articles.article_id = articles.id").
+		Where("series_articles.series_id = ?", seriesID).
+		Scopes(publishedUnless(includeDrafts)).Preload("Author").
+		Order("series_articles.position").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (r *seriesRepository) FindEntry(ctx context.Context, articleID uint) (*domain.SeriesArticle, error) {
+	var entry domain.SeriesArticle
+	if err := conn(ctx, r.db).Where("article_id = ?", articleID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// AddArticle 将文章追加到系列末尾，文章已属于某个系列时返回错误
+func (r *seriesRepository) AddArticle(ctx context.Context, seriesID, articleID uint) (*domain.SeriesArticle, error) {
+	entry := &domain.SeriesArticle{SeriesID: seriesID, ArticleID: articleID}
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.SeriesArticle{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrArticleInSeries
+		}
+
+		var last int
+		if err := tx.Model(&domain.SeriesArticle{}).Where("series_id = ?", seriesID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		entry.Position = last + 1
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return touchSeries(tx, seriesID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RemoveArticle 从系列中移除文章，之后的文章依次前移
+func (r *seriesRepository) RemoveArticle(ctx context.Context, seriesID, articleID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var entry domain.SeriesArticle
+		if err := tx.Where("series_id = ? AND article_id = ?", seriesID, articleID).First(&entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.SeriesArticle{}).
+			Where("series_id = ? AND position > ?", seriesID, entry.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+		return touchSeries(tx, seriesID)
+	})
+}
+
+// Reorder 按 articleIDs 的顺序重新排列系列中的文章，articleIDs 必须恰好包含系列中未删除的全部文章；
+// 回收站中的文章保持原有的相对顺序排在最后，恢复后仍在系列中
+func (r *seriesRepository) Reorder(ctx context.Context, seriesID uint, articleIDs []uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current, trashed []uint
+		entries := func() *gorm.DB {
+			return tx.Model(&domain.SeriesArticle{}).
+				Joins("JOIN articles ON articles.id = series_articles.article_id").
+				Where("series_articles.series_id = ?", seriesID)
+		}
+		if err := entries().Where("articles.deleted_at IS NULL").
+			Pluck("series_articles.article_id", &current).Error; err != nil {
+			return err
+		}
+		if !sameIDs(current, articleIDs) {
+			return domain.ErrSeriesArticlesMismatch
+		}
+		if err := entries().Where("articles.deleted_at IS NOT NULL").Order("series_articles.position").
+			Pluck("series_articles.article_id", &trashed).Error; err != nil {
+			return err
+		}
+
+		for i, id := range slices.Concat(articleIDs, trashed) {
+			if err := tx.Model(&domain.SeriesArticle{}).
+				Where("series_id = ? AND article_id = ?", seriesID, id).
+				UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return touchSeries(tx, seriesID)
+	})
+}
+
+// touchSeries 系列中的文章变化时更新系列的修改时间
+func touchSeries(tx *gorm.DB, seriesID uint) error {
+	return tx.Model(&domain.Series{}).Where("id = ?", seriesID).Update("updated_at", time.Now().UTC()).Error
+}
+
+// sameIDs 判断两组 ID 是否包含相同的元素且 b 中没有重复
+func sameIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[uint]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+// purgeSeries 删除用户创建的系列，用户被彻底删除时调用
+func purgeSeries(tx *gorm.DB, userIDs []uint) error {
+	series := tx.Model(&domain.Series{}).Select("id").Where("author_id IN ?", userIDs)
+	if err := tx.Where("series_id IN (?)", series).Delete(&domain.SeriesArticle{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("author_id IN ?", userIDs).Delete(&domain.Series{}).Error
+}
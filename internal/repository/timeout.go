@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryTimeoutKey = "app:query_timeout"
+
+// queryTimeout 记录单条语句被替换前的上下文，执行完成后恢复，
+// 以免同一个 *gorm.DB 上的后续语句继承已取消的上下文
+type queryTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// registerQueryTimeout 为每条 SQL 在其上下文（通常来自 HTTP 请求）的基础上附加执行时间上限。
+// Row / Rows 的结果在回调结束后才被读取，提前取消会导致读取失败，因此不做限制。
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	before := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.InstanceSet(queryTimeoutKey, &queryTimeout{parent: parent, cancel: cancel})
+		tx.Statement.Context = ctx
+	}
+	after := func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(queryTimeoutKey)
+		if !ok {
+			return
+		}
+		if qt, ok := v.(*queryTimeout); ok && qt != nil {
+			qt.cancel()
+			tx.Statement.Context = qt.parent
+			tx.InstanceSet(queryTimeoutKey, nil)
+		}
+	}
+
+	cb := db.Callback()
+	// 写操作的默认事务在 gorm:begin_transaction 中开启、在 gorm:commit_or_rollback_transaction 中提交，
+	// 超时上下文必须落在二者之间，否则事务会绑定到执行完即被取消的上下文上
+	return errors.Join(
+		cb.Create().After("gorm:begin_transaction").Before("gorm:create").Register("app:timeout_before_create", before),
+		cb.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("app:timeout_after_create", after),
+		cb.Update().After("gorm:begin_transaction").Before("gorm:update").Register("app:timeout_before_update", before),
+		cb.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("app:timeout_after_update", after),
+		cb.Delete().After("gorm:begin_transaction").Before("gorm:delete").Register("app:timeout_before_delete", before),
+		cb.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("app:timeout_after_delete", after),
+		cb.Query().Before("gorm:query").Register("app:timeout_before_query", before),
+		cb.Query().After("gorm:query").Register("app:timeout_after_query", after),
+		cb.Raw().Before("gorm:raw").Register("app:timeout_before_raw", before),
+		cb.Raw().After("gorm:raw").Register("app:timeout_after_raw", after),
+	)
+}
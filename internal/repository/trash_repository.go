@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PurgeResult 彻底删除的数据中需要在数据库之外清理的部分
+type PurgeResult struct {
+	ArticleIDs  []uint // 需要从搜索索引中移除的文章
+	UserIDs     []uint
+	StorageKeys []string // 需要从文件存储中删除的附件和头像
+}
+
+// TrashRepository 彻底删除回收站中超过保留期的数据
+type TrashRepository interface {
+	PurgeArticles(ctx context.Context, before time.Time) (*PurgeResult, error)
+	PurgeUsers(ctx context.Context, before time.Time) (*PurgeResult, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db}
+}
+
+// PurgeArticles 彻底删除 before 之前被删除的文章及其评论、点赞、收藏、附件记录
+// 删除时间以 UTC 存储，SQLite 按文本比较时间，before 同样转换为 UTC。
+func (r *trashRepository) PurgeArticles(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	result := &PurgeResult{}
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.Article{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC()).
+			Pluck("id", &result.ArticleIDs).Error; err != nil {
+			return err
+		}
+		return purgeArticles(tx, result)
+	})
+	return result, err
+}
+
+// PurgeUsers 彻底删除 before 之前被删除的用户、其全部文章和个人数据
+func (r *trashRepository) PurgeUsers(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	result := &PurgeResult{}
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var users []domain.User
+		if err := tx.Unscoped().Select("id", "avatar_key", "avatar_thumb_key").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC()).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		for i := range users {
+			result.UserIDs = append(result.UserIDs, users[i].ID)
+			collectAvatarKeys(result, &users[i])
+		}
+		ids := result.UserIDs
+
+		if err := tx.Unscoped().Model(&domain.Article{}).Where("author_id IN ?", ids).
+			Pluck("id", &result.ArticleIDs).Error; err != nil {
+			return err
+		}
+		if err := purgeArticles(tx, result); err != nil {
+			return err
+		}
+
+		if err := purgeUserData(tx, ids, result, false); err != nil {
+			return err
+		}
+		if err := purgeSeries(tx, ids); err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&domain.User{}).Error
+	})
+	return result, err
+}
+
+// purgeArticles 彻底删除 result.ArticleIDs 中的文章及其关联数据，并收集附件的存储 key
+func purgeArticles(tx *gorm.DB, result *PurgeResult) error {
+	ids := result.ArticleIDs
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var keys []string
+	if err := tx.Model(&domain.Attachment{}).Where("article_id IN ?", ids).
+		Pluck("storage_key", &keys).Error; err != nil {
+		return err
+	}
+	result.StorageKeys = append(result.StorageKeys, keys...)
+
+	comments := tx.Unscoped().Model(&domain.Comment{}).Select("id").Where("article_id IN ?", ids)
+	if err := tx.Where("comment_id IN (?)", comments).Delete(&domain.CommentFlag{}).Error; err != nil {
+		return err
+	}
+	for _, model := range []interface{}{&domain.Comment{}, &domain.ArticleLike{}, &domain.Bookmark{}, &domain.Attachment{}, &domain.ArticleSlugRedirect{}, &domain.ArticleContributor{}, &domain.SeriesArticle{}} {
+		if err := tx.Unscoped().Where("article_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Article{}).Error
+}
+
+func collectAvatarKeys(result *PurgeResult, user *domain.User) {
+	for _, key := range []string{user.AvatarKey, user.AvatarThumbKey} {
+		if key != "" {
+			result.StorageKeys = append(result.StorageKeys, key)
+		}
+	}
+}
+
+// purgeUserData 删除用户的点赞、收藏、举报、关注关系、协作关系和数据导出
+// keepComments 为 false 时删除其评论：没有回复的评论彻底删除，
+// 有回复的评论清空内容后软删除，以已删除状态保留，以维持楼层结构。
+func purgeUserData(tx *gorm.DB, ids []uint, result *PurgeResult, keepComments bool) error {
+	// 其他文章上的点赞与评论被移除后，需要重新计算计数
+	var affected []uint
+	if err := tx.Model(&domain.ArticleLike{}).Where("user_id IN ?", ids).
+		Distinct().Pluck("article_id", &affected).Error; err != nil {
+		return err
+	}
+	if !keepComments {
+		var commented []uint
+		if err := tx.Model(&domain.Comment{}).Where("author_id IN ?", ids).
+			Distinct().Pluck("article_id", &commented).Error; err != nil {
+			return err
+		}
+		affected = append(affected, commented...)
+	}
+
+	var exportKeys []string
+	if err := tx.Model(&domain.DataExport{}).Where("user_id IN ? AND storage_key <> ''", ids).
+		Pluck("storage_key", &exportKeys).Error; err != nil {
+		return err
+	}
+	result.StorageKeys = append(result.StorageKeys, exportKeys...)
+
+	if err := tx.Where("user_id IN ?", ids).Delete(&domain.ArticleLike{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", ids).Delete(&domain.Bookmark{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", ids).Delete(&domain.CommentFlag{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", ids).Delete(&domain.DataExport{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("follower_id IN ? OR followee_id IN ?", ids, ids).Delete(&domain.Follow{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", ids).Delete(&domain.ArticleContributor{}).Error; err != nil {
+		return err
+	}
+	if !keepComments {
+		if err := purgeComments(tx, ids); err != nil {
+			return err
+		}
+	}
+	if len(affected) > 0 {
+		return recountArticles(tx, affected)
+	}
+	return nil
+}
+
+// purgeComments 删除用户的评论。没有回复的评论连同举报记录彻底删除，
+// 删除后其父评论可能也不再有回复，因此逐层重复直到没有可删除的评论；
+// 其余评论清空内容并软删除，数据库中不再保留评论原文。
+func purgeComments(tx *gorm.DB, ids []uint) error {
+	for {
+		var leaves []uint
+		if err := tx.Unscoped().Model(&domain.Comment{}).Where("author_id IN ?", ids).
+			Where("NOT EXISTS (SELECT 1 FROM comments AS replies WHERE replies.parent_id = comments.id)").
+			Pluck("id", &leaves).Error; err != nil {
+			return err
+		}
+		if len(leaves) == 0 {
+			break
+		}
+		if err := tx.Where("comment_id IN ?", leaves).Delete(&domain.CommentFlag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&domain.Comment{}, leaves).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Unscoped().Model(&domain.Comment{}).Where("author_id IN ?", ids).UpdateColumns(map[string]interface{}{
+		"content":    "",
+		"deleted_at": gorm.Expr("COALESCE(deleted_at, ?)", time.Now().UTC()),
+	}).Error
+}
+
+// recountArticles 根据现存数据重新计算文章的点赞数和评论数
+func recountArticles(tx *gorm.DB, ids []uint) error {
+	likes := tx.Model(&domain.ArticleLike{}).Select("COUNT(*)").Where("article_likes.article_id = articles.id")
+	comments := tx.Model(&domain.Comment{}).Select("COUNT(*)").
+		Where("comments.article_id = articles.id AND comments.status = ?", domain.CommentStatusVisible)
+	return tx.Unscoped().Model(&domain.Article{}).Where("id IN ?", ids).UpdateColumns(map[string]interface{}{
+		"like_count":    likes,
+		"comment_count": comments,
+	}).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// TxManager 在一个数据库事务中执行多个仓储调用
+type TxManager interface {
+	// WithinTransaction 开启事务并执行 fn，fn 返回错误或 panic 时回滚。
+	// fn 内使用传入的 ctx 调用任意仓储方法都会自动加入该事务；
+	// 嵌套调用时使用保存点，内层失败只回滚内层的修改。
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db}
+}
+
+// txKey 事务在上下文中的键
+type txKey struct{}
+
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn 返回绑定到 ctx 的数据库会话，ctx 处于事务中时返回该事务。
+// 仓储方法一律通过 conn 访问数据库，才能被 TxManager 透明地纳入事务。
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// translateError 将唯一约束冲突转换为 domain.ErrAlreadyExists，同时保留原始错误
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrAlreadyExists.Wrap(err)
+	}
+	return err
+}
@@ -1,18 +1,24 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Anning01/user-management/internal/domain"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *domain.User) error
-	FindByID(id uint) (*domain.User, error)
-	FindByEmail(email string) (*domain.User, error)
-	FindByUsername(username string) (*domain.User, error)
-	Update(user *domain.User) error
-	Delete(id uint) error
+	Create(ctx context.Context, user *domain.User) error
+	FindByID(ctx context.Context, id uint) (*domain.User, error)
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id uint, deletedAt time.Time) error
+	FindDeleted(ctx context.Context, limit, offset int) ([]domain.User, int64, error)
+	FindDeletedByID(ctx context.Context, id uint) (*domain.User, error)
+	Restore(ctx context.Context, id uint) error
 }
 
 type userRepository struct {
@@ -23,38 +29,85 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db}
 }
 
-func (r *userRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return translateError(conn(ctx, r.db).Create(user).Error)
 }
 
-func (r *userRepository) FindByID(id uint) (*domain.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByUsername(username string) (*domain.User, error) {
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	return translateError(conn(ctx, r.db).Save(user).Error)
+}
+
+// Delete 以指定的删除时间软删除用户
+func (r *userRepository) Delete(ctx context.Context, id uint, deletedAt time.Time) error {
+	result := conn(ctx, r.db).Model(&domain.User{}).Where("id = ?", id).Update("deleted_at", deletedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindDeleted 查询回收站中的用户，按删除时间倒序
+func (r *userRepository) FindDeleted(ctx context.Context, limit, offset int) ([]domain.User, int64, error) {
+	var users []domain.User
+	var total int64
+
+	query := conn(ctx, r.db).Unscoped().Model(&domain.User{}).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("deleted_at desc").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) FindDeletedByID(ctx context.Context, id uint) (*domain.User, error) {
+	var user domain.User
+	if err := conn(ctx, r.db).Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.User{}, id).Error
+// Restore 恢复用户，以及随用户一起删除的文章
+func (r *userRepository) Restore(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&domain.Article{}).
+			Where("author_id = ? AND deleted_at = ?", id, user.DeletedAt.Time).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&user).Update("deleted_at", nil).Error
+	})
 }
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+
+	// snippetLength 摘要片段的最大字符数
+	snippetLength = 160
+)
+
+// matchMask 标记 text 中与任一词条匹配的字符位置
+// 中日韩词条按子串匹配，其余词条要求落在单词边界上，避免 "go" 命中 "good"。
+func matchMask(runes []rune, terms []string) []bool {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	mask := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		wordTerm := !isCJK(t[0])
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if !equalRunes(lower[i:i+len(t)], t) {
+				continue
+			}
+			if wordTerm {
+				if i > 0 && isWordRune(lower[i-1]) {
+					continue
+				}
+				if end := i + len(t); end < len(lower) && isWordRune(lower[end]) {
+					continue
+				}
+			}
+			for j := i; j < i+len(t); j++ {
+				mask[j] = true
+			}
+		}
+	}
+	return mask
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// render 将 runes[start:end] 转义为 HTML，并用 <mark> 包裹命中的片段
+func render(runes []rune, mask []bool, start, end int) string {
+	var b strings.Builder
+	open := false
+	for i := start; i < end; i++ {
+		if mask[i] && !open {
+			b.WriteString(highlightOpen)
+			open = true
+		} else if !mask[i] && open {
+			b.WriteString(highlightClose)
+			open = false
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+	}
+	if open {
+		b.WriteString(highlightClose)
+	}
+	return b.String()
+}
+
+// Highlight 返回高亮后的完整文本（已做 HTML 转义）
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	return render(runes, matchMask(runes, terms), 0, len(runes))
+}
+
+// Snippet 截取第一个命中位置附近的文本片段并高亮
+// 没有命中时返回文本开头的片段。
+func Snippet(text string, terms []string) string {
+	runes := []rune(text)
+	mask := matchMask(runes, terms)
+
+	first := 0
+	for i, hit := range mask {
+		if hit {
+			first = i
+			break
+		}
+	}
+
+	start := first - snippetLength/4
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - snippetLength; start < 0 {
+			start = 0
+		}
+	}
+
+	s := render(runes, mask, start, end)
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(runes) {
+		s += "…"
+	}
+	return s
+}
+
+// buildHighlights 为搜索结果生成标题和正文的高亮内容
+func buildHighlights(title, content string, terms []string) map[string]string {
+	return map[string]string{
+		"title":   Highlight(title, terms),
+		"content": Snippet(content, terms),
+	}
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendMySQL  = "mysql"
+)
+
+// Query 搜索条件
+type Query struct {
+	Text          string
+	AuthorID      uint
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Limit         int
+	Offset        int
+}
+
+// Hit 单条搜索命中结果
+type Hit struct {
+	ID         uint
+	Score      float64
+	Highlights map[string]string
+}
+
+// SearchIndex 文章全文索引
+// Index / Remove 在文章创建、更新、删除时调用，以保持索引与数据库同步。
+type SearchIndex interface {
+	Index(ctx context.Context, article *domain.Article) error
+	Remove(ctx context.Context, id uint) error
+	Search(ctx context.Context, q Query) ([]Hit, int64, error)
+}
+
+// NewIndex 根据配置的后端创建搜索索引
+func NewIndex(backend string, db *gorm.DB) (SearchIndex, error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewMemoryIndex(), nil
+	case BackendMySQL:
+		// FULLTEXT 索引只在 MySQL 上创建
+		if db.Dialector.Name() != "mysql" {
+			return nil, fmt.Errorf("search backend %s requires the mysql database driver", backend)
+		}
+		return NewMySQLIndex(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported search backend: %s", backend)
+	}
+}
+
+// matches 判断文档是否满足过滤条件
+func (q Query) matches(authorID uint, createdAt time.Time) bool {
+	if q.AuthorID != 0 && authorID != q.AuthorID {
+		return false
+	}
+	if !q.CreatedAfter.IsZero() && createdAt.Before(q.CreatedAfter) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && !createdAt.Before(q.CreatedBefore) {
+		return false
+	}
+	return true
+}
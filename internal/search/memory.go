@@ -0,0 +1,174 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// titleBoost 标题中的词频权重
+	titleBoost = 3
+)
+
+type memoryDoc struct {
+	authorID  uint
+	createdAt time.Time
+	title     string
+	content   string
+	length    int
+	terms     []string
+}
+
+// memoryIndex 纯 Go 实现的内存倒排索引
+// 启动时需要通过 ArticleService.RebuildSearchIndex 从数据库重建。
+type memoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*memoryDoc
+	postings map[string]map[uint]int // 词条 -> 文档ID -> 加权词频
+	totalLen int
+}
+
+func NewMemoryIndex() SearchIndex {
+	return &memoryIndex{
+		docs:     make(map[uint]*memoryDoc),
+		postings: make(map[string]map[uint]int),
+	}
+}
+
+func (m *memoryIndex) Index(ctx context.Context, article *domain.Article) error {
+	freq := make(map[string]int)
+	length := 0
+	for _, t := range Tokenize(article.Title) {
+		freq[t] += titleBoost
+		length++
+	}
+	for _, t := range Tokenize(article.Content) {
+		freq[t]++
+		length++
+	}
+
+	doc := &memoryDoc{
+		authorID:  article.AuthorID,
+		createdAt: article.CreatedAt,
+		title:     article.Title,
+		content:   article.Content,
+		length:    length,
+		terms:     make([]string, 0, len(freq)),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeLocked(article.ID)
+	for term, tf := range freq {
+		docs, ok := m.postings[term]
+		if !ok {
+			docs = make(map[uint]int)
+			m.postings[term] = docs
+		}
+		docs[article.ID] = tf
+		doc.terms = append(doc.terms, term)
+	}
+	m.docs[article.ID] = doc
+	m.totalLen += length
+
+	return nil
+}
+
+func (m *memoryIndex) Remove(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeLocked(id)
+	return nil
+}
+
+func (m *memoryIndex) removeLocked(id uint) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		delete(m.postings[term], id)
+		if len(m.postings[term]) == 0 {
+			delete(m.postings, term)
+		}
+	}
+	m.totalLen -= doc.length
+	delete(m.docs, id)
+}
+
+func (m *memoryIndex) Search(ctx context.Context, q Query) ([]Hit, int64, error) {
+	terms := uniqueTerms(Tokenize(q.Text))
+	if len(terms) == 0 {
+		return []Hit{}, 0, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	n := float64(len(m.docs))
+	avgLen := 1.0
+	if len(m.docs) > 0 && m.totalLen > 0 {
+		avgLen = float64(m.totalLen) / n
+	}
+
+	scores := make(map[uint]float64)
+	for _, term := range terms {
+		docs := m.postings[term]
+		if len(docs) == 0 {
+			continue
+		}
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range docs {
+			doc := m.docs[id]
+			if !q.matches(doc.authorID, doc.createdAt) {
+				continue
+			}
+			f := float64(tf)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(doc.length)/avgLen)
+			scores[id] += idf * f * (bm25K1 + 1) / (f + norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return m.docs[hits[i].ID].createdAt.After(m.docs[hits[j].ID].createdAt)
+	})
+
+	total := int64(len(hits))
+	hits = paginate(hits, q.Limit, q.Offset)
+	for i := range hits {
+		doc := m.docs[hits[i].ID]
+		hits[i].Highlights = buildHighlights(doc.title, doc.content, terms)
+	}
+
+	return hits, total, nil
+}
+
+func paginate(hits []Hit, limit, offset int) []Hit {
+	if offset >= len(hits) {
+		return []Hit{}
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// matchExpr 使用 articles 表上的 FULLTEXT 索引（ngram 解析器，支持中文）
+const matchExpr = "MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+// mysqlIndex 基于 MySQL FULLTEXT 索引的搜索实现
+// 索引由数据库自身维护，因此 Index / Remove 无需任何操作。
+type mysqlIndex struct {
+	db *gorm.DB
+}
+
+func NewMySQLIndex(db *gorm.DB) SearchIndex {
+	return &mysqlIndex{db}
+}
+
+func (m *mysqlIndex) Index(ctx context.Context, article *domain.Article) error {
+	return nil
+}
+
+func (m *mysqlIndex) Remove(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *mysqlIndex) Search(ctx context.Context, q Query) ([]Hit, int64, error) {
+	terms := uniqueTerms(Tokenize(q.Text))
+	if len(terms) == 0 {
+		return []Hit{}, 0, nil
+	}
+
+	// 与内存索引一致，只搜索已发布的文章；软删除由 gorm 自动排除
+	query := m.db.WithContext(ctx).Model(&domain.Article{}).Where(matchExpr, q.Text).
+		Where("status = ?", domain.ArticleStatusPublished)
+	if q.AuthorID != 0 {
+		query = query.Where("author_id = ?", q.AuthorID)
+	}
+	if !q.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", q.CreatedBefore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ID      uint
+		Title   string
+		Content string
+		Score   float64
+	}
+	if err := query.Select("id, title, content, "+matchExpr+" AS score", q.Text).
+		Order("score desc, created_at desc").
+		Limit(q.Limit).Offset(q.Offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, Hit{
+			ID:         row.ID,
+			Score:      row.Score,
+			Highlights: buildHighlights(row.Title, row.Content, terms),
+		})
+	}
+
+	return hits, total, nil
+}
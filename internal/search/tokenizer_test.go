@@ -0,0 +1,33 @@
+package search_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/search"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Hello, World!", []string{"hello", "world"}},
+		{"Go1.24 released", []string{"go1", "24", "released"}},
+		// 中日韩文字按二元组切分，单字片段保留为一元词条
+		{"中文分词", []string{"中文", "文分", "分词"}},
+		{"中", []string{"中"}},
+		{"我 爱", []string{"我", "爱"}},
+		{"学习Golang编程", []string{"学习", "golang", "编程"}},
+		{"Go语言，入门。", []string{"go", "语言", "入门"}},
+		{"ひらがなカタカナ", []string{"ひら", "らが", "がな", "なカ", "カタ", "タカ", "カナ"}},
+		{"한국어", []string{"한국", "국어"}},
+		{"Café naïve", []string{"café", "naïve"}},
+	}
+	for _, tc := range cases {
+		if got := search.Tokenize(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
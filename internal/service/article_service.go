@@ -1,48 +1,222 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Anning01/user-management/internal/domain"
 	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/pkg/listquery"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/pagination"
+	"github.com/Anning01/user-management/pkg/render"
+	"github.com/Anning01/user-management/pkg/slug"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// reindexBatchSize 重建搜索索引时每批读取的文章数量
+	reindexBatchSize = 500
+	// renderCacheSize 缓存渲染结果的文章数量上限
+	renderCacheSize = 1000
+	// defaultSlug 标题无法生成 slug 时使用的默认值
+	defaultSlug = "article"
 )
 
 type ArticleService interface {
-	CreateArticle(article *domain.Article) error
-	GetArticleByID(id uint) (*domain.Article, error)
-	ListArticles(page, pageSize int) ([]domain.Article, int64, error)
-	ListArticlesByAuthor(authorID uint, page, pageSize int) ([]domain.Article, int64, error)
-	UpdateArticle(id, authorID uint, title, content string) error
-	DeleteArticle(id, authorID uint) error
+	CreateArticle(ctx context.Context, article *domain.Article) error
+	GetArticleByID(ctx context.Context, id, viewerID uint) (*domain.Article, error)
+	GetArticleBySlug(ctx context.Context, slug string, viewerID uint) (*domain.Article, bool, error)
+	ListArticles(ctx context.Context, page, pageSize int, q *listquery.Query) ([]domain.Article, int64, error)
+	ListArticlesByAuthor(ctx context.Context, authorID uint, includeDrafts bool, page, pageSize int, q *listquery.Query) ([]domain.Article, int64, error)
+	ListArticlesByCursor(ctx context.Context, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, pagination.PageInfo, error)
+	ListArticlesByAuthorCursor(ctx context.Context, authorID uint, includeDrafts bool, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, pagination.PageInfo, error)
+	ListSharedArticles(ctx context.Context, userID uint, page, pageSize int) ([]domain.Article, int64, error)
+	ListFeed(ctx context.Context, userID uint, page pagination.CursorPage) ([]domain.Article, pagination.PageInfo, error)
+	UpdateArticle(ctx context.Context, id, userID uint, title, content, contentFormat, slug, status string) error
+	DeleteArticle(ctx context.Context, id, userID uint) error
+	ListContributors(ctx context.Context, articleID, userID uint) ([]domain.ArticleContributor, error)
+	AddContributor(ctx context.Context, articleID, userID, contributorID uint, role string) (*domain.ArticleContributor, error)
+	RemoveContributor(ctx context.Context, articleID, userID, contributorID uint) error
+	RenderArticle(article *domain.Article) error
+	SearchArticles(ctx context.Context, q search.Query, page, pageSize int) ([]domain.ArticleSearchResult, int64, error)
+	RebuildSearchIndex(ctx context.Context) error
 }
 
 type articleService struct {
-	articleRepo repository.ArticleRepository
-	userRepo    repository.UserRepository
+	articleRepo     repository.ArticleRepository
+	contributorRepo repository.ContributorRepository
+	seriesRepo      repository.SeriesRepository
+	userRepo        repository.UserRepository
+	txManager       repository.TxManager
+	searchIndex     search.SearchIndex
+	renderCache     *render.Cache
 }
 
-func NewArticleService(articleRepo repository.ArticleRepository, userRepo repository.UserRepository) ArticleService {
+func NewArticleService(articleRepo repository.ArticleRepository, contributorRepo repository.ContributorRepository, seriesRepo repository.SeriesRepository, userRepo repository.UserRepository, txManager repository.TxManager, searchIndex search.SearchIndex) ArticleService {
 	return &articleService{
-		articleRepo: articleRepo,
-		userRepo:    userRepo,
+		articleRepo:     articleRepo,
+		contributorRepo: contributorRepo,
+		seriesRepo:      seriesRepo,
+		userRepo:        userRepo,
+		txManager:       txManager,
+		searchIndex:     searchIndex,
+		renderCache:     render.NewCache(renderCacheSize),
 	}
 }
 
-func (s *articleService) CreateArticle(article *domain.Article) error {
+func (s *articleService) CreateArticle(ctx context.Context, article *domain.Article) error {
 	// 验证作者是否存在
-	_, err := s.userRepo.FindByID(article.AuthorID)
+	_, err := s.userRepo.FindByID(ctx, article.AuthorID)
 	if err != nil {
-		return errors.New("author not found")
+		return domain.ErrAuthorNotFound
+	}
+
+	if article.ContentFormat == "" {
+		article.ContentFormat = render.FormatPlain
+	}
+	if !render.ValidFormat(article.ContentFormat) {
+		return domain.ErrUnsupportedContentFormat
 	}
+	article.Excerpt = render.Excerpt(article.ContentFormat, article.Content)
 
-	return s.articleRepo.Create(article)
+	if article.Status == "" {
+		article.Status = domain.ArticleStatusPublished
+	}
+	if !validArticleStatus(article.Status) {
+		return domain.ErrInvalidArticleStatus
+	}
+
+	if article.Slug, err = s.resolveSlug(ctx, article.Slug, article.Title, 0); err != nil {
+		return err
+	}
+
+	if err := s.articleRepo.Create(ctx, article); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.ErrSlugInUse
+		}
+		return err
+	}
+
+	s.indexArticle(ctx, article)
+	return nil
 }
 
-func (s *articleService) GetArticleByID(id uint) (*domain.Article, error) {
-	return s.articleRepo.FindByID(id)
+// GetArticleByID 获取文章及其系列导航，草稿仅对作者与协作者可见，viewerID 为 0 表示未登录
+func (s *articleService) GetArticleByID(ctx context.Context, id, viewerID uint) (*domain.Article, error) {
+	article, err := s.articleRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkVisible(ctx, article, viewerID); err != nil {
+		return nil, err
+	}
+	if article.Series, err = s.seriesNav(ctx, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// GetArticleBySlug 按 slug 获取文章，第二个返回值表示 slug 是否为已停用的旧 slug
+func (s *articleService) GetArticleBySlug(ctx context.Context, slug string, viewerID uint) (*domain.Article, bool, error) {
+	article, err := s.articleRepo.FindBySlug(ctx, slug)
+	moved := false
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		redirect, err := s.articleRepo.FindSlugRedirect(ctx, slug)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, domain.ErrArticleNotFound
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		article, err = s.articleRepo.FindByID(ctx, redirect.ArticleID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, domain.ErrArticleNotFound
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		moved = true
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.checkVisible(ctx, article, viewerID); err != nil {
+		return nil, false, err
+	}
+	if article.Series, err = s.seriesNav(ctx, article); err != nil {
+		return nil, false, err
+	}
+	return article, moved, nil
+}
+
+// seriesNav 生成文章所属系列的位置与上一篇、下一篇导航，文章不属于任何系列时返回 nil
+// 导航跳过系列中的草稿，当前文章是草稿时仍按其在系列中的位置计算。
+func (s *articleService) seriesNav(ctx context.Context, article *domain.Article) (*domain.SeriesNav, error) {
+	entry, err := s.seriesRepo.FindEntry(ctx, article.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.seriesRepo.FindByID(ctx, entry.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := s.seriesRepo.FindArticles(ctx, series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := articles[:0]
+	for _, a := range articles {
+		if a.Published() || a.ID == article.ID {
+			visible = append(visible, a)
+		}
+	}
+
+	nav := &domain.SeriesNav{ID: series.ID, Title: series.Title, Total: len(visible)}
+	for i, a := range visible {
+		if a.ID != article.ID {
+			continue
+		}
+		nav.Position = i + 1
+		if i > 0 {
+			nav.Previous = articleRef(&visible[i-1])
+		}
+		if i < len(visible)-1 {
+			nav.Next = articleRef(&visible[i+1])
+		}
+	}
+	return nav, nil
+}
+
+func articleRef(article *domain.Article) *domain.ArticleRef {
+	return &domain.ArticleRef{ID: article.ID, Title: article.Title, Slug: article.Slug}
+}
+
+// checkVisible 草稿对没有任何角色的用户表现为不存在
+func (s *articleService) checkVisible(ctx context.Context, article *domain.Article, viewerID uint) error {
+	if article.Published() {
+		return nil
+	}
+	role, err := articleRole(ctx, s.contributorRepo, article, viewerID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return domain.ErrArticleNotFound
+	}
+	return nil
 }
 
-func (s *articleService) ListArticles(page, pageSize int) ([]domain.Article, int64, error) {
+func (s *articleService) ListArticles(ctx context.Context, page, pageSize int, q *listquery.Query) ([]domain.Article, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -51,10 +225,11 @@ func (s *articleService) ListArticles(page, pageSize int) ([]domain.Article, int
 	}
 
 	offset := (page - 1) * pageSize
-	return s.articleRepo.FindAll(pageSize, offset)
+	return s.articleRepo.FindAll(ctx, pageSize, offset, q)
 }
 
-func (s *articleService) ListArticlesByAuthor(authorID uint, page, pageSize int) ([]domain.Article, int64, error) {
+// ListArticlesByAuthor 获取作者的文章，includeDrafts 为 true 时包括草稿
+func (s *articleService) ListArticlesByAuthor(ctx context.Context, authorID uint, includeDrafts bool, page, pageSize int, q *listquery.Query) ([]domain.Article, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -63,36 +238,392 @@ func (s *articleService) ListArticlesByAuthor(authorID uint, page, pageSize int)
 	}
 
 	offset := (page - 1) * pageSize
-	return s.articleRepo.FindByAuthorID(authorID, pageSize, offset)
+	return s.articleRepo.FindByAuthorID(ctx, authorID, includeDrafts, pageSize, offset, q)
 }
 
-func (s *articleService) UpdateArticle(id, authorID uint, title, content string) error {
-	article, err := s.articleRepo.FindByID(id)
+// ListSharedArticles 获取用户作为协作者参与的文章
+func (s *articleService) ListSharedArticles(ctx context.Context, userID uint, page, pageSize int) ([]domain.Article, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	return s.contributorRepo.FindArticlesByUserID(ctx, userID, pageSize, offset)
+}
+
+func (s *articleService) ListArticlesByCursor(ctx context.Context, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, pagination.PageInfo, error) {
+	page.Limit = normalizePageSize(page.Limit)
+	articles, err := s.articleRepo.FindAllByCursor(ctx, page, q)
+	if err != nil {
+		return nil, pagination.PageInfo{}, err
+	}
+
+	articles, info := pagination.Trim(articles, page, ArticleCursor)
+	return articles, info, nil
+}
+
+func (s *articleService) ListArticlesByAuthorCursor(ctx context.Context, authorID uint, includeDrafts bool, page pagination.CursorPage, q *listquery.Query) ([]domain.Article, pagination.PageInfo, error) {
+	page.Limit = normalizePageSize(page.Limit)
+	articles, err := s.articleRepo.FindByAuthorIDByCursor(ctx, authorID, includeDrafts, page, q)
+	if err != nil {
+		return nil, pagination.PageInfo{}, err
+	}
+
+	articles, info := pagination.Trim(articles, page, ArticleCursor)
+	return articles, info, nil
+}
+
+// ListFeed 获取用户关注的作者发布的文章
+func (s *articleService) ListFeed(ctx context.Context, userID uint, page pagination.CursorPage) ([]domain.Article, pagination.PageInfo, error) {
+	page.Limit = normalizePageSize(page.Limit)
+	articles, err := s.articleRepo.FindFeedByCursor(ctx, userID, page)
+	if err != nil {
+		return nil, pagination.PageInfo{}, err
+	}
+
+	articles, info := pagination.Trim(articles, page, ArticleCursor)
+	return articles, info, nil
+}
+
+// resolveSlug 校验指定的 slug 是否可用；未指定时根据标题生成不重复的 slug
+func (s *articleService) resolveSlug(ctx context.Context, requested, title string, articleID uint) (string, error) {
+	taken := func(candidate string) (bool, error) {
+		return s.articleRepo.SlugTaken(ctx, candidate, articleID)
+	}
+
+	if requested == "" {
+		return slug.Unique(slug.Make(title), defaultSlug, taken)
+	}
+
+	if !slug.Valid(requested) {
+		return "", domain.ErrInvalidSlug
+	}
+	used, err := taken(requested)
+	if err != nil {
+		return "", err
+	}
+	if used {
+		return "", domain.ErrSlugInUse
+	}
+	return requested, nil
+}
+
+// ArticleCursor 返回指向该文章的分页游标
+func ArticleCursor(article domain.Article) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: article.CreatedAt, ID: article.ID}
+}
+
+func normalizePageSize(pageSize int) int {
+	if pageSize < 1 || pageSize > 100 {
+		return 10
+	}
+	return pageSize
+}
+
+// UpdateArticle 更新文章，contentFormat、slug 或 status 为空时保留原值
+// 文章作者、所有者与编辑可以更新；修改 slug 后旧 slug 仍可访问并重定向到新 slug。
+func (s *articleService) UpdateArticle(ctx context.Context, id, userID uint, title, content, contentFormat, newSlug, status string) error {
+	article, err := s.articleRepo.FindByID(ctx, id)
 	if err != nil {
-		return errors.New("article not found")
+		return domain.ErrArticleNotFound
 	}
 
-	// 验证是否是文章作者
-	if article.AuthorID != authorID {
-		return errors.New("permission denied")
+	role, err := articleRole(ctx, s.contributorRepo, article, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" && !article.Published() {
+		return domain.ErrArticleNotFound
+	}
+	if !domain.CanEdit(role) {
+		return domain.ErrPermissionDenied
 	}
 
+	if status != "" {
+		if !validArticleStatus(status) {
+			return domain.ErrInvalidArticleStatus
+		}
+		article.Status = status
+	}
+
+	if contentFormat != "" {
+		if !render.ValidFormat(contentFormat) {
+			return domain.ErrUnsupportedContentFormat
+		}
+		article.ContentFormat = contentFormat
+	}
+	oldSlug := article.Slug
+	if newSlug != "" && newSlug != oldSlug {
+		if article.Slug, err = s.resolveSlug(ctx, newSlug, title, id); err != nil {
+			return err
+		}
+	}
 	article.Title = title
 	article.Content = content
+	article.Excerpt = render.Excerpt(article.ContentFormat, article.Content)
+	// 关联对象不随文章保存
+	article.CoAuthors = nil
+
+	// 文章与旧 slug 的重定向一起写入，避免出现无法访问的旧链接
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.Update(ctx, article); err != nil {
+			return err
+		}
+		if article.Slug != oldSlug {
+			return s.articleRepo.RetireSlug(ctx, id, oldSlug, article.Slug)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.ErrSlugInUse
+		}
+		return err
+	}
+	s.renderCache.Delete(id)
+
+	s.indexArticle(ctx, article)
+	return nil
+}
+
+// DeleteArticle 删除文章，仅所有者可以删除
+func (s *articleService) DeleteArticle(ctx context.Context, id, userID uint) error {
+	article, err := s.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.ErrArticleNotFound
+	}
+
+	role, err := articleRole(ctx, s.contributorRepo, article, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" && !article.Published() {
+		return domain.ErrArticleNotFound
+	}
+	if role != domain.ContributorRoleOwner {
+		return domain.ErrPermissionDenied
+	}
+
+	if err := s.articleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.renderCache.Delete(id)
+
+	if err := s.searchIndex.Remove(ctx, id); err != nil {
+		logger.Error(ctx, "failed to remove article from search index", "article_id", id, logger.Err(err))
+	}
+	return nil
+}
+
+// RenderArticle 填充文章的 rendered_html 与目录，优先使用缓存
+func (s *articleService) RenderArticle(article *domain.Article) error {
+	result, ok := s.renderCache.Get(article.ID, article.UpdatedAt)
+	if !ok {
+		var err error
+		result, err = render.Render(article.ContentFormat, article.Content)
+		if err != nil {
+			return err
+		}
+		s.renderCache.Put(article.ID, article.UpdatedAt, result)
+	}
+
+	article.RenderedHTML = result.HTML
+	article.TOC = result.TOC
+	return nil
+}
+
+func (s *articleService) SearchArticles(ctx context.Context, q search.Query, page, pageSize int) ([]domain.ArticleSearchResult, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	q.Limit = pageSize
+	q.Offset = (page - 1) * pageSize
+	hits, total, err := s.searchIndex.Search(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	articles, err := s.articleRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]domain.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	// 按相关度顺序组装结果，跳过索引中存在但已被删除或转为草稿的文章，
+	// 总数同时扣除被跳过的条数，不返回包含这些文章的索引总数
+	results := make([]domain.ArticleSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		article, ok := byID[hit.ID]
+		if !ok || !article.Published() {
+			continue
+		}
+		results = append(results, domain.ArticleSearchResult{
+			Article:    article,
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+	total -= int64(len(hits) - len(results))
+
+	return results, total, nil
+}
+
+// RebuildSearchIndex 从数据库重新加载全部文章到搜索索引
+func (s *articleService) RebuildSearchIndex(ctx context.Context) error {
+	for offset := 0; ; offset += reindexBatchSize {
+		articles, _, err := s.articleRepo.FindAll(ctx, reindexBatchSize, offset, nil)
+		if err != nil {
+			return err
+		}
+		for i := range articles {
+			if err := s.searchIndex.Index(ctx, &articles[i]); err != nil {
+				return err
+			}
+		}
+		if len(articles) < reindexBatchSize {
+			return nil
+		}
+	}
+}
+
+// ListContributors 获取文章的全部协作者（包括查看者），仅文章的协作者可以查看
+func (s *articleService) ListContributors(ctx context.Context, articleID, userID uint) ([]domain.ArticleContributor, error) {
+	if _, err := s.requireRole(ctx, articleID, userID); err != nil {
+		return nil, err
+	}
+	return s.contributorRepo.FindByArticleID(ctx, articleID)
+}
+
+// AddContributor 邀请协作者或修改已有协作者的角色，仅所有者可以操作
+func (s *articleService) AddContributor(ctx context.Context, articleID, userID, contributorID uint, role string) (*domain.ArticleContributor, error) {
+	if !domain.ValidContributorRole(role) {
+		return nil, domain.ErrInvalidContributorRole
+	}
+
+	article, err := s.requireRole(ctx, articleID, userID, domain.ContributorRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if contributorID == article.AuthorID {
+		return nil, domain.ErrCannotChangeAuthorRole
+	}
+
+	user, err := s.userRepo.FindByID(ctx, contributorID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	contributor := &domain.ArticleContributor{
+		ArticleID: articleID,
+		UserID:    contributorID,
+		Role:      role,
+		InvitedBy: userID,
+	}
+	if err := s.contributorRepo.Save(ctx, contributor); err != nil {
+		return nil, err
+	}
+	contributor.User = *user
+	return contributor, nil
+}
+
+// RemoveContributor 移除协作者，所有者可以移除他人，协作者可以退出
+func (s *articleService) RemoveContributor(ctx context.Context, articleID, userID, contributorID uint) error {
+	var article *domain.Article
+	var err error
+	if contributorID == userID {
+		article, err = s.requireRole(ctx, articleID, userID)
+	} else {
+		article, err = s.requireRole(ctx, articleID, userID, domain.ContributorRoleOwner)
+	}
+	if err != nil {
+		return err
+	}
+	if contributorID == article.AuthorID {
+		return domain.ErrCannotRemoveAuthor
+	}
+
+	if _, err := s.contributorRepo.Find(ctx, articleID, contributorID); err != nil {
+		return domain.ErrContributorNotFound
+	}
+	return s.contributorRepo.Delete(ctx, articleID, contributorID)
+}
 
-	return s.articleRepo.Update(article)
+// requireRole 校验 userID 在文章中的角色，roles 为空时只要求是协作者
+// 对文章没有任何角色的用户看不到草稿，返回 article not found。
+func (s *articleService) requireRole(ctx context.Context, articleID, userID uint, roles ...string) (*domain.Article, error) {
+	article, err := s.articleRepo.FindByID(ctx, articleID)
+	if err != nil {
+		return nil, domain.ErrArticleNotFound
+	}
+
+	role, err := articleRole(ctx, s.contributorRepo, article, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		if !article.Published() {
+			return nil, domain.ErrArticleNotFound
+		}
+		return nil, domain.ErrPermissionDenied
+	}
+	if len(roles) == 0 {
+		return article, nil
+	}
+	for _, r := range roles {
+		if role == r {
+			return article, nil
+		}
+	}
+	return nil, domain.ErrPermissionDenied
 }
 
-func (s *articleService) DeleteArticle(id, authorID uint) error {
-	article, err := s.articleRepo.FindByID(id)
+// articleRole 返回 userID 在文章中的角色，文章作者始终是所有者，没有角色时返回空字符串
+func articleRole(ctx context.Context, contributorRepo repository.ContributorRepository, article *domain.Article, userID uint) (string, error) {
+	if userID == 0 {
+		return "", nil
+	}
+	if article.AuthorID == userID {
+		return domain.ContributorRoleOwner, nil
+	}
+	contributor, err := contributorRepo.Find(ctx, article.ID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
 	if err != nil {
-		return errors.New("article not found")
+		return "", err
 	}
+	return contributor.Role, nil
+}
+
+func validArticleStatus(status string) bool {
+	return status == domain.ArticleStatusDraft || status == domain.ArticleStatusPublished
+}
 
-	// 验证是否是文章作者
-	if article.AuthorID != authorID {
-		return errors.New("permission denied")
+// indexArticle 同步文章到搜索索引，失败时只记录日志，不影响主流程
+func (s *articleService) indexArticle(ctx context.Context, article *domain.Article) {
+	if err := syncSearchIndex(ctx, s.searchIndex, article); err != nil {
+		logger.Error(ctx, "failed to index article", "article_id", article.ID, logger.Err(err))
 	}
+}
 
-	return s.articleRepo.Delete(id)
+// syncSearchIndex 已发布的文章写入搜索索引，草稿从索引中移除
+func syncSearchIndex(ctx context.Context, searchIndex search.SearchIndex, article *domain.Article) error {
+	if !article.Published() {
+		return searchIndex.Remove(ctx, article.ID)
+	}
+	return searchIndex.Index(ctx, article)
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/storage"
+)
+
+type AttachmentService interface {
+	Upload(ctx context.Context, articleID, userID uint, fileName string, size int64, r io.Reader) (*domain.Attachment, error)
+	ListByArticle(ctx context.Context, articleID, viewerID uint) ([]domain.Attachment, error)
+	Delete(ctx context.Context, articleID, attachmentID, userID uint) error
+}
+
+type attachmentService struct {
+	attachmentRepo  repository.AttachmentRepository
+	articleRepo     repository.ArticleRepository
+	contributorRepo repository.ContributorRepository
+	store           storage.Storage
+	limits          UploadLimits
+	urlExpiry       time.Duration
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, articleRepo repository.ArticleRepository, contributorRepo repository.ContributorRepository, store storage.Storage, limits UploadLimits, urlExpiry time.Duration) AttachmentService {
+	return &attachmentService{
+		attachmentRepo:  attachmentRepo,
+		articleRepo:     articleRepo,
+		contributorRepo: contributorRepo,
+		store:           store,
+		limits:          limits,
+		urlExpiry:       urlExpiry,
+	}
+}
+
+// Upload 为文章上传附件，文章的所有者与编辑可以上传
+func (s *attachmentService) Upload(ctx context.Context, articleID, userID uint, fileName string, size int64, r io.Reader) (*domain.Attachment, error) {
+	if err := s.checkEditor(ctx, articleID, userID); err != nil {
+		return nil, err
+	}
+
+	file, err := checkUpload(r, size, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("attachments/%d", articleID), file.Extension)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, file.Reader, size, file.ContentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		ArticleID:   articleID,
+		UploaderID:  userID,
+		StorageKey:  key,
+		FileName:    filepath.Base(fileName),
+		ContentType: file.ContentType,
+		Size:        size,
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// 记录写入失败时清理已上传的文件
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			logger.Error(ctx, "failed to delete orphaned upload", "key", key, logger.Err(delErr))
+		}
+		return nil, err
+	}
+
+	if attachment.URL, err = s.store.SignedURL(ctx, key, s.urlExpiry); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ListByArticle 获取文章附件，并为每个附件生成下载地址；草稿的附件仅协作者可见
+func (s *attachmentService) ListByArticle(ctx context.Context, articleID, viewerID uint) ([]domain.Attachment, error) {
+	article, err := s.articleRepo.FindByID(ctx, articleID)
+	if err != nil {
+		return nil, domain.ErrArticleNotFound
+	}
+	if !article.Published() {
+		role, err := articleRole(ctx, s.contributorRepo, article, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, domain.ErrArticleNotFound
+		}
+	}
+
+	attachments, err := s.attachmentRepo.FindByArticleID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range attachments {
+		if attachments[i].URL, err = s.store.SignedURL(ctx, attachments[i].StorageKey, s.urlExpiry); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// Delete 删除附件及其文件，文章的所有者与编辑可以删除
+func (s *attachmentService) Delete(ctx context.Context, articleID, attachmentID, userID uint) error {
+	if err := s.checkEditor(ctx, articleID, userID); err != nil {
+		return err
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(ctx, attachmentID)
+	if err != nil || attachment.ArticleID != articleID {
+		return domain.ErrAttachmentNotFound
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		logger.Error(ctx, "failed to delete attachment file", "key", attachment.StorageKey, logger.Err(err))
+	}
+	return nil
+}
+
+func (s *attachmentService) checkEditor(ctx context.Context, articleID, userID uint) error {
+	article, err := s.articleRepo.FindByID(ctx, articleID)
+	if err != nil {
+		return domain.ErrArticleNotFound
+	}
+	role, err := articleRole(ctx, s.contributorRepo, article, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" && !article.Published() {
+		return domain.ErrArticleNotFound
+	}
+	if !domain.CanEdit(role) {
+		return domain.ErrPermissionDenied
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/pkg/imaging"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/storage"
+)
+
+// AvatarTypes 头像允许的图片类型，需能被 pkg/imaging 解码
+var AvatarTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+type AvatarService interface {
+	Upload(ctx context.Context, userID uint, size int64, r io.Reader) (*domain.User, error)
+	Delete(ctx context.Context, userID uint) error
+	// URL 返回用户头像的下载地址，thumbnail 为 true 时返回缩略图
+	URL(ctx context.Context, userID uint, thumbnail bool) (string, error)
+}
+
+type avatarService struct {
+	userRepo  repository.UserRepository
+	store     storage.Storage
+	limits    UploadLimits
+	thumbSize int
+	urlExpiry time.Duration
+}
+
+func NewAvatarService(userRepo repository.UserRepository, store storage.Storage, limits UploadLimits, thumbSize int, urlExpiry time.Duration) AvatarService {
+	return &avatarService{
+		userRepo:  userRepo,
+		store:     store,
+		limits:    limits,
+		thumbSize: thumbSize,
+		urlExpiry: urlExpiry,
+	}
+}
+
+// Upload 上传头像并生成缩略图，替换原有头像
+func (s *avatarService) Upload(ctx context.Context, userID uint, size int64, r io.Reader) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	file, err := checkUpload(r, size, s.limits)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	thumb, thumbType, err := imaging.Thumbnail(bytes.NewReader(data), s.thumbSize)
+	if errors.Is(err, imaging.ErrImageTooLarge) {
+		return nil, domain.ErrImageTooLarge
+	}
+	if err != nil {
+		return nil, domain.ErrInvalidImage
+	}
+
+	prefix := fmt.Sprintf("avatars/%d", userID)
+	key, err := newStorageKey(prefix, file.Extension)
+	if err != nil {
+		return nil, err
+	}
+	thumbKey, err := newStorageKey(prefix, extensionFor(thumbType))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), file.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+		s.deleteFiles(ctx, key)
+		return nil, err
+	}
+
+	oldKeys := []string{user.AvatarKey, user.AvatarThumbKey}
+	user.AvatarKey = key
+	user.AvatarThumbKey = thumbKey
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.deleteFiles(ctx, key, thumbKey)
+		return nil, err
+	}
+
+	s.deleteFiles(ctx, oldKeys...)
+	return user, nil
+}
+
+// Delete 删除用户头像
+func (s *avatarService) Delete(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	if user.AvatarKey == "" {
+		return nil
+	}
+
+	oldKeys := []string{user.AvatarKey, user.AvatarThumbKey}
+	user.AvatarKey = ""
+	user.AvatarThumbKey = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.deleteFiles(ctx, oldKeys...)
+	return nil
+}
+
+func (s *avatarService) URL(ctx context.Context, userID uint, thumbnail bool) (string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", domain.ErrUserNotFound
+	}
+
+	key := user.AvatarKey
+	if thumbnail {
+		key = user.AvatarThumbKey
+	}
+	if key == "" {
+		return "", domain.ErrAvatarNotFound
+	}
+	return s.store.SignedURL(ctx, key, s.urlExpiry)
+}
+
+// deleteFiles 删除不再使用的文件，失败时只记录日志
+func (s *avatarService) deleteFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error(ctx, "failed to delete avatar file", "key", key, logger.Err(err))
+		}
+	}
+}
+
+func extensionFor(contentType string) string {
+	if contentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+)
+
+type CommentService interface {
+	CreateComment(ctx context.Context, comment *domain.Comment) error
+	ListComments(ctx context.Context, articleID uint) ([]*domain.Comment, error)
+	UpdateComment(ctx context.Context, articleID, id, userID uint, content string) (*domain.Comment, error)
+	DeleteComment(ctx context.Context, articleID, id, userID uint) error
+	FlagComment(ctx context.Context, articleID, id, userID uint, reason string) error
+	ListFlaggedComments(ctx context.Context, page, pageSize int) ([]domain.Comment, int64, error)
+	ApproveComment(ctx context.Context, id uint) error
+	RejectComment(ctx context.Context, id uint) error
+}
+
+type commentService struct {
+	commentRepo     repository.CommentRepository
+	articleRepo     repository.ArticleRepository
+	contributorRepo repository.ContributorRepository
+	userRepo        repository.UserRepository
+	txManager       repository.TxManager
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, articleRepo repository.ArticleRepository, contributorRepo repository.ContributorRepository, userRepo repository.UserRepository, txManager repository.TxManager) CommentService {
+	return &commentService{
+		commentRepo:     commentRepo,
+		articleRepo:     articleRepo,
+		contributorRepo: contributorRepo,
+		userRepo:        userRepo,
+		txManager:       txManager,
+	}
+}
+
+func (s *commentService) CreateComment(ctx context.Context, comment *domain.Comment) error {
+	// 草稿不开放评论
+	if article, err := s.articleRepo.FindByID(ctx, comment.ArticleID); err != nil || !article.Published() {
+		return domain.ErrArticleNotFound
+	}
+
+	// 回复的评论必须属于同一篇文章且仍然可见
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(ctx, *comment.ParentID)
+		if err != nil || parent.ArticleID != comment.ArticleID || !parent.Visible() {
+			return domain.ErrParentCommentNotFound
+		}
+	}
+
+	comment.Status = domain.CommentStatusVisible
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Create(ctx, comment); err != nil {
+			return err
+		}
+		return s.articleRepo.UpdateCommentCount(ctx, comment.ArticleID, 1)
+	})
+}
+
+// ListComments 返回文章的评论树
+// 已删除或被隐藏的评论如果还有可见的回复，会以占位节点保留，以维持楼层结构。
+func (s *commentService) ListComments(ctx context.Context, articleID uint) ([]*domain.Comment, error) {
+	if article, err := s.articleRepo.FindByID(ctx, articleID); err != nil || !article.Published() {
+		return nil, domain.ErrArticleNotFound
+	}
+
+	comments, err := s.commentRepo.FindThreadByArticleID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*domain.Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	roots := make([]*domain.Comment, 0)
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		if parent, ok := byID[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+		}
+	}
+
+	return pruneThread(roots), nil
+}
+
+// pruneThread 遮蔽不可见评论的内容，并移除没有可见回复的不可见评论
+func pruneThread(comments []*domain.Comment) []*domain.Comment {
+	kept := comments[:0]
+	for _, comment := range comments {
+		comment.Replies = pruneThread(comment.Replies)
+		if comment.Visible() {
+			kept = append(kept, comment)
+			continue
+		}
+		if len(comment.Replies) == 0 {
+			continue
+		}
+		comment.Deleted = true
+		comment.Content = ""
+		comment.AuthorID = 0
+		comment.Author = domain.User{}
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+func (s *commentService) UpdateComment(ctx context.Context, articleID, id, userID uint, content string) (*domain.Comment, error) {
+	comment, err := s.findComment(ctx, articleID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 被审核隐藏的评论不能再修改
+	if !comment.Visible() {
+		return nil, domain.ErrCommentNotFound
+	}
+	// 只有评论作者可以修改评论
+	if comment.AuthorID != userID {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	comment.Content = content
+	if err := s.commentRepo.UpdateContent(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, articleID, id, userID uint) error {
+	comment, err := s.findComment(ctx, articleID, id)
+	if err != nil {
+		return err
+	}
+
+	// 评论作者、文章所有者（作者或 owner 角色的协作者）和管理员可以删除评论
+	if comment.AuthorID != userID {
+		article, err := s.articleRepo.FindByID(ctx, articleID)
+		if err != nil {
+			return domain.ErrArticleNotFound
+		}
+		role, err := articleRole(ctx, s.contributorRepo, article, userID)
+		if err != nil {
+			return err
+		}
+		if role != domain.ContributorRoleOwner && !s.isAdmin(ctx, userID) {
+			return domain.ErrPermissionDenied
+		}
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if !comment.Visible() {
+			return nil
+		}
+		return s.articleRepo.UpdateCommentCount(ctx, articleID, -1)
+	})
+}
+
+func (s *commentService) FlagComment(ctx context.Context, articleID, id, userID uint, reason string) error {
+	comment, err := s.findComment(ctx, articleID, id)
+	if err != nil {
+		return err
+	}
+	if !comment.Visible() {
+		return domain.ErrCommentNotFound
+	}
+
+	_, err = s.commentRepo.AddFlag(ctx, &domain.CommentFlag{
+		CommentID: id,
+		UserID:    userID,
+		Reason:    reason,
+	})
+	return err
+}
+
+func (s *commentService) ListFlaggedComments(ctx context.Context, page, pageSize int) ([]domain.Comment, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+
+	offset := (page - 1) * pageSize
+	return s.commentRepo.FindFlagged(ctx, pageSize, offset)
+}
+
+// ApproveComment 审核通过：保留评论并移出审核队列
+func (s *commentService) ApproveComment(ctx context.Context, id uint) error {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.ErrCommentNotFound
+	}
+
+	comment.Flagged = false
+	return s.commentRepo.UpdateModeration(ctx, comment)
+}
+
+// RejectComment 审核拒绝：隐藏评论并移出审核队列
+func (s *commentService) RejectComment(ctx context.Context, id uint) error {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.ErrCommentNotFound
+	}
+
+	wasVisible := comment.Visible()
+	comment.Flagged = false
+	comment.Status = domain.CommentStatusHidden
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.UpdateModeration(ctx, comment); err != nil {
+			return err
+		}
+		if !wasVisible {
+			return nil
+		}
+		return s.articleRepo.UpdateCommentCount(ctx, comment.ArticleID, -1)
+	})
+}
+
+// findComment 查询评论并确认其属于指定文章
+func (s *commentService) findComment(ctx context.Context, articleID, id uint) (*domain.Comment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil || comment.ArticleID != articleID {
+		return nil, domain.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func (s *commentService) isAdmin(ctx context.Context, userID uint) bool {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	return err == nil && user.IsAdmin()
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+)
+
+type EngagementService interface {
+	LikeArticle(ctx context.Context, userID, articleID uint) error
+	UnlikeArticle(ctx context.Context, userID, articleID uint) error
+	BookmarkArticle(ctx context.Context, userID, articleID uint) error
+	UnbookmarkArticle(ctx context.Context, userID, articleID uint) error
+	ListBookmarks(ctx context.Context, userID uint, page, pageSize int) ([]domain.Article, int64, error)
+	AnnotateArticles(ctx context.Context, userID uint, articles []domain.Article) error
+	RecordView(articleID uint, viewer string)
+}
+
+type engagementService struct {
+	engagementRepo repository.EngagementRepository
+	articleRepo    repository.ArticleRepository
+	viewCounter    *ViewCounter
+}
+
+func NewEngagementService(engagementRepo repository.EngagementRepository, articleRepo repository.ArticleRepository, viewCounter *ViewCounter) EngagementService {
+	return &engagementService{
+		engagementRepo: engagementRepo,
+		articleRepo:    articleRepo,
+		viewCounter:    viewCounter,
+	}
+}
+
+func (s *engagementService) LikeArticle(ctx context.Context, userID, articleID uint) error {
+	if err := s.checkArticle(ctx, articleID); err != nil {
+		return err
+	}
+	return s.engagementRepo.AddLike(ctx, userID, articleID)
+}
+
+func (s *engagementService) UnlikeArticle(ctx context.Context, userID, articleID uint) error {
+	if err := s.checkArticle(ctx, articleID); err != nil {
+		return err
+	}
+	return s.engagementRepo.RemoveLike(ctx, userID, articleID)
+}
+
+func (s *engagementService) BookmarkArticle(ctx context.Context, userID, articleID uint) error {
+	if err := s.checkArticle(ctx, articleID); err != nil {
+		return err
+	}
+	return s.engagementRepo.AddBookmark(ctx, userID, articleID)
+}
+
+func (s *engagementService) UnbookmarkArticle(ctx context.Context, userID, articleID uint) error {
+	if err := s.checkArticle(ctx, articleID); err != nil {
+		return err
+	}
+	return s.engagementRepo.RemoveBookmark(ctx, userID, articleID)
+}
+
+func (s *engagementService) ListBookmarks(ctx context.Context, userID uint, page, pageSize int) ([]domain.Article, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+
+	offset := (page - 1) * pageSize
+	articles, total, err := s.engagementRepo.FindBookmarkedArticles(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.AnnotateArticles(ctx, userID, articles); err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+// AnnotateArticles 为文章填充当前用户的点赞、收藏状态
+func (s *engagementService) AnnotateArticles(ctx context.Context, userID uint, articles []domain.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+
+	liked, err := s.engagementRepo.FindLikedArticleIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+	bookmarked, err := s.engagementRepo.FindBookmarkedArticleIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		isLiked, isBookmarked := liked[articles[i].ID], bookmarked[articles[i].ID]
+		articles[i].LikedByMe = &isLiked
+		articles[i].BookmarkedByMe = &isBookmarked
+	}
+	return nil
+}
+
+// RecordView 记录一次浏览，由 ViewCounter 去重并批量写入
+func (s *engagementService) RecordView(articleID uint, viewer string) {
+	s.viewCounter.Record(articleID, viewer)
+}
+
+// checkArticle 文章必须存在且已发布，草稿不能点赞或收藏
+func (s *engagementService) checkArticle(ctx context.Context, articleID uint) error {
+	if article, err := s.articleRepo.FindByID(ctx, articleID); err != nil || !article.Published() {
+		return domain.ErrArticleNotFound
+	}
+	return nil
+}
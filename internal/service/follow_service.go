@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID, followeeID uint) error
+	Unfollow(ctx context.Context, followerID, followeeID uint) error
+	IsFollowing(ctx context.Context, followerID, followeeID uint) (bool, error)
+	ListFollowers(ctx context.Context, userID uint, page, pageSize int) ([]domain.User, int64, error)
+	ListFollowing(ctx context.Context, userID uint, page, pageSize int) ([]domain.User, int64, error)
+	GetFollowCounts(ctx context.Context, userID uint) (followers, following int64, err error)
+}
+
+type followService struct {
+	followRepo repository.FollowRepository
+	userRepo   repository.UserRepository
+}
+
+func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository) FollowService {
+	return &followService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *followService) Follow(ctx context.Context, followerID, followeeID uint) error {
+	if followerID == followeeID {
+		return domain.ErrCannotFollowSelf
+	}
+	if _, err := s.userRepo.FindByID(ctx, followeeID); err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	return s.followRepo.Create(ctx, &domain.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+}
+
+func (s *followService) Unfollow(ctx context.Context, followerID, followeeID uint) error {
+	return s.followRepo.Delete(ctx, followerID, followeeID)
+}
+
+func (s *followService) IsFollowing(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	return s.followRepo.Exists(ctx, followerID, followeeID)
+}
+
+func (s *followService) ListFollowers(ctx context.Context, userID uint, page, pageSize int) ([]domain.User, int64, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, 0, domain.ErrUserNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+
+	offset := (page - 1) * pageSize
+	return s.followRepo.FindFollowers(ctx, userID, pageSize, offset)
+}
+
+func (s *followService) ListFollowing(ctx context.Context, userID uint, page, pageSize int) ([]domain.User, int64, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, 0, domain.ErrUserNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+
+	offset := (page - 1) * pageSize
+	return s.followRepo.FindFollowing(ctx, userID, pageSize, offset)
+}
+
+func (s *followService) GetFollowCounts(ctx context.Context, userID uint) (int64, int64, error) {
+	followers, err := s.followRepo.CountFollowers(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	following, err := s.followRepo.CountFollowing(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/security"
+	"github.com/Anning01/user-management/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyService 个人数据导出与账号注销
+type PrivacyService interface {
+	// RequestExport 创建导出任务，压缩包由 PrivacyWorker 在后台生成
+	RequestExport(ctx context.Context, userID uint) (*domain.DataExport, error)
+	ListExports(ctx context.Context, userID uint) ([]domain.DataExport, error)
+	GetExport(ctx context.Context, id, userID uint) (*domain.DataExport, error)
+	// ExportRequested 有新的导出任务时收到通知
+	ExportRequested() <-chan struct{}
+	ProcessPendingExports(ctx context.Context) (int, error)
+	ExpireExports(ctx context.Context, now time.Time) (int, error)
+
+	// RequestErasure 申请注销账号，冷静期结束后执行，期间可以撤销
+	RequestErasure(ctx context.Context, userID uint, password string, keepArticles bool) (*domain.ErasureRequest, error)
+	GetErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error)
+	CancelErasure(ctx context.Context, userID uint) error
+	ExecuteDueErasures(ctx context.Context, now time.Time) (int, error)
+}
+
+type privacyService struct {
+	privacyRepo repository.PrivacyRepository
+	userRepo    repository.UserRepository
+	searchIndex search.SearchIndex
+	store       storage.Storage
+	coolingOff  time.Duration
+	expiry      time.Duration
+	urlExpiry   time.Duration
+
+	requested chan struct{}
+}
+
+func NewPrivacyService(privacyRepo repository.PrivacyRepository, userRepo repository.UserRepository, searchIndex search.SearchIndex, store storage.Storage, coolingOff, expiry, urlExpiry time.Duration) PrivacyService {
+	return &privacyService{
+		privacyRepo: privacyRepo,
+		userRepo:    userRepo,
+		searchIndex: searchIndex,
+		store:       store,
+		coolingOff:  coolingOff,
+		expiry:      expiry,
+		urlExpiry:   urlExpiry,
+		requested:   make(chan struct{}, 1),
+	}
+}
+
+func (s *privacyService) RequestExport(ctx context.Context, userID uint) (*domain.DataExport, error) {
+	exports, err := s.privacyRepo.FindExportsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		if export.Status == domain.ExportStatusPending || export.Status == domain.ExportStatusProcessing {
+			return nil, domain.ErrExportInProgress
+		}
+	}
+
+	export := &domain.DataExport{UserID: userID, Status: domain.ExportStatusPending}
+	if err := s.privacyRepo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	// 通知后台任务，通道已满时说明已有待处理的通知
+	select {
+	case s.requested <- struct{}{}:
+	default:
+	}
+	return export, nil
+}
+
+func (s *privacyService) ListExports(ctx context.Context, userID uint) ([]domain.DataExport, error) {
+	exports, err := s.privacyRepo.FindExportsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exports {
+		if err := s.signExport(ctx, &exports[i]); err != nil {
+			return nil, err
+		}
+	}
+	return exports, nil
+}
+
+func (s *privacyService) GetExport(ctx context.Context, id, userID uint) (*domain.DataExport, error) {
+	export, err := s.privacyRepo.FindExportByID(ctx, id)
+	if err != nil || export.UserID != userID {
+		return nil, domain.ErrExportNotFound
+	}
+	if err := s.signExport(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *privacyService) signExport(ctx context.Context, export *domain.DataExport) error {
+	if export.Status != domain.ExportStatusReady || export.StorageKey == "" {
+		return nil
+	}
+	url, err := s.store.SignedURL(ctx, export.StorageKey, s.urlExpiry)
+	if err != nil {
+		return err
+	}
+	export.URL = url
+	return nil
+}
+
+func (s *privacyService) ExportRequested() <-chan struct{} {
+	return s.requested
+}
+
+// ProcessPendingExports 生成所有等待中的导出，上次中断的任务会重新生成
+func (s *privacyService) ProcessPendingExports(ctx context.Context) (int, error) {
+	exports, err := s.privacyRepo.FindExportsByStatus(ctx, domain.ExportStatusPending, domain.ExportStatusProcessing)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range exports {
+		export := &exports[i]
+		export.Status = domain.ExportStatusProcessing
+		if err := s.privacyRepo.UpdateExport(ctx, export); err != nil {
+			return i, err
+		}
+
+		now := time.Now().UTC()
+		export.CompletedAt = &now
+		if err := s.buildExport(ctx, export); err != nil {
+			logger.Error(ctx, "failed to build data export", "export_id", export.ID, logger.Err(err))
+			export.Status = domain.ExportStatusFailed
+			export.Error = err.Error()
+		} else {
+			expiresAt := now.Add(s.expiry)
+			export.Status = domain.ExportStatusReady
+			export.ExpiresAt = &expiresAt
+		}
+		if err := s.privacyRepo.UpdateExport(ctx, export); err != nil {
+			return i, err
+		}
+	}
+	return len(exports), nil
+}
+
+// buildExport 将用户数据打包为 zip 并保存到文件存储
+// 压缩包包含完整数据 data.json、Markdown 格式的概要和文章，以及头像和附件原文件。
+func (s *privacyService) buildExport(ctx context.Context, export *domain.DataExport) error {
+	data, err := s.privacyRepo.CollectUserData(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.writeArchive(ctx, tmp, data); err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("exports/%d", export.UserID), ".zip")
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, key, tmp, size, "application/zip"); err != nil {
+		return err
+	}
+	export.StorageKey = key
+	export.Size = size
+	return nil
+}
+
+func (s *privacyService) writeArchive(ctx context.Context, w io.Writer, data *domain.PersonalData) error {
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create("data.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return err
+	}
+
+	f, err = zw.Create("README.md")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, exportSummary(data)); err != nil {
+		return err
+	}
+
+	for i := range data.Articles {
+		article := &data.Articles[i]
+		content, err := articleMarkdown(article)
+		if err != nil {
+			return err
+		}
+		f, err := zw.Create(articleFileName(article))
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if data.Profile.AvatarKey != "" {
+		name := "avatar" + path.Ext(data.Profile.AvatarKey)
+		if err := s.copyFile(ctx, zw, name, data.Profile.AvatarKey); err != nil {
+			return err
+		}
+	}
+	for _, attachment := range data.Attachments {
+		name := fmt.Sprintf("attachments/%d-%s", attachment.ID, path.Base(attachment.FileName))
+		if err := s.copyFile(ctx, zw, name, attachment.StorageKey); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// copyFile 将存储中的文件写入压缩包，文件已不存在时跳过
+func (s *privacyService) copyFile(ctx context.Context, zw *zip.Writer, name, key string) error {
+	r, _, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func exportSummary(data *domain.PersonalData) string {
+	var b strings.Builder
+	b.WriteString("# 个人数据导出\n\n")
+	fmt.Fprintf(&b, "导出时间：%s\n\n", data.ExportedAt.Format(time.RFC3339))
+	b.WriteString("## 账号\n\n")
+	fmt.Fprintf(&b, "- 用户名：%s\n", data.Profile.Username)
+	fmt.Fprintf(&b, "- 邮箱：%s\n", data.Profile.Email)
+	fmt.Fprintf(&b, "- 姓名：%s\n", data.Profile.FullName)
+	fmt.Fprintf(&b, "- 注册时间：%s\n\n", data.Profile.CreatedAt.Format(time.RFC3339))
+	b.WriteString("## 内容\n\n")
+	fmt.Fprintf(&b, "- 文章：%d（见 articles/ 目录）\n", len(data.Articles))
+	fmt.Fprintf(&b, "- 评论：%d\n", len(data.Comments))
+	fmt.Fprintf(&b, "- 点赞：%d\n", len(data.Likes))
+	fmt.Fprintf(&b, "- 收藏：%d\n", len(data.Bookmarks))
+	fmt.Fprintf(&b, "- 关注：%d\n", len(data.Following))
+	fmt.Fprintf(&b, "- 粉丝：%d\n", len(data.Followers))
+	fmt.Fprintf(&b, "- 附件：%d（见 attachments/ 目录）\n\n", len(data.Attachments))
+	b.WriteString("完整数据见 data.json。\n")
+	return b.String()
+}
+
+// ExpireExports 删除过期的导出及其文件
+func (s *privacyService) ExpireExports(ctx context.Context, now time.Time) (int, error) {
+	keys, err := s.privacyRepo.DeleteExpiredExports(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error(ctx, "failed to delete export file", "key", key, logger.Err(err))
+		}
+	}
+	return len(keys), nil
+}
+
+func (s *privacyService) RequestErasure(ctx context.Context, userID uint, password string, keepArticles bool) (*domain.ErasureRequest, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if err := security.CheckPasswordHash(password, user.Password); err != nil {
+		return nil, domain.ErrInvalidPassword
+	}
+	_, err = s.privacyRepo.FindPendingErasure(ctx, userID)
+	if err == nil {
+		return nil, domain.ErrErasureRequested
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	request := &domain.ErasureRequest{
+		UserID:       userID,
+		KeepArticles: keepArticles,
+		Status:       domain.ErasureStatusPending,
+		ScheduledAt:  time.Now().UTC().Add(s.coolingOff),
+	}
+	if err := s.privacyRepo.CreateErasureRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *privacyService) GetErasure(ctx context.Context, userID uint) (*domain.ErasureRequest, error) {
+	request, err := s.privacyRepo.FindPendingErasure(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrErasureNotFound
+	}
+	return request, nil
+}
+
+func (s *privacyService) CancelErasure(ctx context.Context, userID uint) error {
+	request, err := s.privacyRepo.FindPendingErasure(ctx, userID)
+	if err != nil {
+		return domain.ErrErasureNotFound
+	}
+	request.Status = domain.ErasureStatusCancelled
+	return s.privacyRepo.UpdateErasureRequest(ctx, request)
+}
+
+// ExecuteDueErasures 执行冷静期已结束的注销请求
+func (s *privacyService) ExecuteDueErasures(ctx context.Context, now time.Time) (int, error) {
+	requests, err := s.privacyRepo.FindDueErasures(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range requests {
+		request := &requests[i]
+		result, err := s.privacyRepo.EraseUser(ctx, request.UserID, request.KeepArticles)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return i, err
+		}
+		if result != nil {
+			s.cleanup(ctx, result)
+		}
+
+		completedAt := time.Now().UTC()
+		request.Status = domain.ErasureStatusCompleted
+		request.CompletedAt = &completedAt
+		if err := s.privacyRepo.UpdateErasureRequest(ctx, request); err != nil {
+			return i, err
+		}
+	}
+	return len(requests), nil
+}
+
+// cleanup 清理数据库之外的数据，失败时只记录日志
+func (s *privacyService) cleanup(ctx context.Context, result *repository.PurgeResult) {
+	for _, id := range result.ArticleIDs {
+		if err := s.searchIndex.Remove(ctx, id); err != nil {
+			logger.Error(ctx, "failed to remove article from search index", "article_id", id, logger.Err(err))
+		}
+	}
+	for _, key := range result.StorageKeys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error(ctx, "failed to delete file", "key", key, logger.Err(err))
+		}
+	}
+}
+
+// PrivacyWorker 在后台生成数据导出、执行到期的注销请求并清理过期导出
+type PrivacyWorker struct {
+	privacyService PrivacyService
+	interval       time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewPrivacyWorker(privacyService PrivacyService, interval time.Duration) *PrivacyWorker {
+	return &PrivacyWorker{
+		privacyService: privacyService,
+		interval:       interval,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// Start 启动后台任务，启动时立即执行一次，以继续上次未完成的导出
+func (w *PrivacyWorker) Start() {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		w.runScheduled()
+		for {
+			select {
+			case <-w.privacyService.ExportRequested():
+				w.processExports()
+			case <-ticker.C:
+				w.runScheduled()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台任务，正在生成的导出会在下次启动时重新生成
+func (w *PrivacyWorker) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *PrivacyWorker) runScheduled() {
+	w.processExports()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	if n, err := w.privacyService.ExecuteDueErasures(ctx, now); err != nil {
+		logger.Error(ctx, "failed to execute erasure requests", logger.Err(err))
+	} else if n > 0 {
+		logger.Info(ctx, "erased users", "count", n)
+	}
+	if _, err := w.privacyService.ExpireExports(ctx, now); err != nil {
+		logger.Error(ctx, "failed to expire data exports", logger.Err(err))
+	}
+}
+
+func (w *PrivacyWorker) processExports() {
+	ctx := context.Background()
+	if _, err := w.privacyService.ProcessPendingExports(ctx); err != nil {
+		logger.Error(ctx, "failed to process data exports", logger.Err(err))
+	}
+}
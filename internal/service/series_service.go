@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type SeriesService interface {
+	CreateSeries(ctx context.Context, series *domain.Series) error
+	GetSeries(ctx context.Context, id, viewerID uint) (*domain.Series, error)
+	ListSeriesByAuthor(ctx context.Context, authorID uint, page, pageSize int) ([]domain.Series, int64, error)
+	UpdateSeries(ctx context.Context, id, userID uint, title, description string) error
+	DeleteSeries(ctx context.Context, id, userID uint) error
+	AddArticle(ctx context.Context, seriesID, userID, articleID uint) (*domain.SeriesArticle, error)
+	RemoveArticle(ctx context.Context, seriesID, userID, articleID uint) error
+	ReorderArticles(ctx context.Context, seriesID, userID uint, articleIDs []uint) error
+}
+
+type seriesService struct {
+	seriesRepo      repository.SeriesRepository
+	articleRepo     repository.ArticleRepository
+	contributorRepo repository.ContributorRepository
+	userRepo        repository.UserRepository
+}
+
+func NewSeriesService(seriesRepo repository.SeriesRepository, articleRepo repository.ArticleRepository, contributorRepo repository.ContributorRepository, userRepo repository.UserRepository) SeriesService {
+	return &seriesService{
+		seriesRepo:      seriesRepo,
+		articleRepo:     articleRepo,
+		contributorRepo: contributorRepo,
+		userRepo:        userRepo,
+	}
+}
+
+func (s *seriesService) CreateSeries(ctx context.Context, series *domain.Series) error {
+	author, err := s.userRepo.FindByID(ctx, series.AuthorID)
+	if err != nil {
+		return domain.ErrAuthorNotFound
+	}
+	if err := s.seriesRepo.Create(ctx, series); err != nil {
+		return err
+	}
+	series.Author = *author
+	return nil
+}
+
+// GetSeries 获取系列及其中按顺序排列的文章，草稿仅对系列作者可见
+func (s *seriesService) GetSeries(ctx context.Context, id, viewerID uint) (*domain.Series, error) {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrSeriesNotFound
+	}
+
+	includeDrafts := viewerID != 0 && viewerID == series.AuthorID
+	if series.Articles, err = s.seriesRepo.FindArticles(ctx, id, includeDrafts); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *seriesService) ListSeriesByAuthor(ctx context.Context, authorID uint, page, pageSize int) ([]domain.Series, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	return s.seriesRepo.FindByAuthorID(ctx, authorID, pageSize, offset)
+}
+
+func (s *seriesService) UpdateSeries(ctx context.Context, id, userID uint, title, description string) error {
+	series, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	series.Title = title
+	series.Description = description
+	return s.seriesRepo.Update(ctx, series)
+}
+
+// DeleteSeries 删除系列，其中的文章不受影响
+func (s *seriesService) DeleteSeries(ctx context.Context, id, userID uint) error {
+	if _, err := s.findOwned(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.seriesRepo.Delete(ctx, id)
+}
+
+// AddArticle 将文章追加到系列末尾，要求当前用户可以编辑该文章
+func (s *seriesService) AddArticle(ctx context.Context, seriesID, userID, articleID uint) (*domain.SeriesArticle, error) {
+	if _, err := s.findOwned(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+
+	article, err := s.articleRepo.FindByID(ctx, articleID)
+	if err != nil {
+		return nil, domain.ErrArticleNotFound
+	}
+	role, err := articleRole(ctx, s.contributorRepo, article, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" && !article.Published() {
+		return nil, domain.ErrArticleNotFound
+	}
+	if !domain.CanEdit(role) {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	return s.seriesRepo.AddArticle(ctx, seriesID, articleID)
+}
+
+func (s *seriesService) RemoveArticle(ctx context.Context, seriesID, userID, articleID uint) error {
+	if _, err := s.findOwned(ctx, seriesID, userID); err != nil {
+		return err
+	}
+	err := s.seriesRepo.RemoveArticle(ctx, seriesID, articleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrArticleNotInSeries
+	}
+	return err
+}
+
+// ReorderArticles 按给定顺序重新排列系列中未删除的全部文章
+func (s *seriesService) ReorderArticles(ctx context.Context, seriesID, userID uint, articleIDs []uint) error {
+	if _, err := s.findOwned(ctx, seriesID, userID); err != nil {
+		return err
+	}
+	return s.seriesRepo.Reorder(ctx, seriesID, articleIDs)
+}
+
+// findOwned 查询系列并验证当前用户是系列作者
+func (s *seriesService) findOwned(ctx context.Context, id, userID uint) (*domain.Series, error) {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrSeriesNotFound
+	}
+	if series.AuthorID != userID {
+		return nil, domain.ErrPermissionDenied
+	}
+	return series, nil
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/domain/errs"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/internal/util"
+	"github.com/Anning01/user-management/pkg/frontmatter"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/render"
+	"github.com/Anning01/user-management/pkg/slug"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// importBatchSize 导入时每个事务写入的文章数量
+	importBatchSize = 100
+	// exportBatchSize 导出时每批读取的文章数量
+	exportBatchSize = 500
+	// maxImportLineSize JSONL 单行的最大字节数
+	maxImportLineSize = 10 << 20
+)
+
+// ImportOptions 批量导入选项
+type ImportOptions struct {
+	AuthorID    uint // 文章的作者，AllowAuthor 为 true 时仅用于未指定 author_id 的记录
+	AllowAuthor bool // 是否按记录中的 author_id 指定作者（管理员）
+	DryRun      bool // 只校验并报告结果，不写入数据库
+}
+
+// TransferService 文章批量导入导出
+type TransferService interface {
+	// ExportArticles 导出文章，authorID 为 0 时导出全部作者的文章，返回导出的数量
+	ExportArticles(ctx context.Context, w io.Writer, format string, authorID uint) (int, error)
+	// ImportArticles 导入文章，按作者与 external_id 匹配已有文章并更新，否则新建
+	ImportArticles(ctx context.Context, r io.ReaderAt, size int64, format string, opts ImportOptions) (*domain.ImportReport, error)
+}
+
+type transferService struct {
+	articleRepo repository.ArticleRepository
+	userRepo    repository.UserRepository
+	searchIndex search.SearchIndex
+}
+
+func NewTransferService(articleRepo repository.ArticleRepository, userRepo repository.UserRepository, searchIndex search.SearchIndex) TransferService {
+	return &transferService{
+		articleRepo: articleRepo,
+		userRepo:    userRepo,
+		searchIndex: searchIndex,
+	}
+}
+
+// TransferFormatFromFileName 根据文件扩展名推断导入格式：.zip 为 markdown，其余为 jsonl
+func TransferFormatFromFileName(name string) string {
+	if strings.EqualFold(path.Ext(name), ".zip") {
+		return domain.TransferFormatMarkdown
+	}
+	return domain.TransferFormatJSONL
+}
+
+func (s *transferService) ExportArticles(ctx context.Context, w io.Writer, format string, authorID uint) (int, error) {
+	switch format {
+	case domain.TransferFormatJSONL:
+		enc := json.NewEncoder(w)
+		return s.eachArticle(ctx, authorID, func(article *domain.Article) error {
+			return enc.Encode(domain.NewArticleRecord(article))
+		})
+	case domain.TransferFormatMarkdown:
+		zw := zip.NewWriter(w)
+		n, err := s.eachArticle(ctx, authorID, func(article *domain.Article) error {
+			data, err := articleMarkdown(article)
+			if err != nil {
+				return err
+			}
+			f, err := zw.Create(articleFileName(article))
+			if err != nil {
+				return err
+			}
+			_, err = f.Write(data)
+			return err
+		})
+		if err != nil {
+			return n, err
+		}
+		return n, zw.Close()
+	default:
+		return 0, domain.ErrUnsupportedTransferFormat
+	}
+}
+
+func (s *transferService) eachArticle(ctx context.Context, authorID uint, fn func(*domain.Article) error) (int, error) {
+	var count int
+	var afterID uint
+	for {
+		articles, err := s.articleRepo.FindAfterID(ctx, authorID, afterID, exportBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for i := range articles {
+			if err := fn(&articles[i]); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(articles) < exportBatchSize {
+			return count, nil
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+// importRow 读取到的一条导入记录
+type importRow struct {
+	row    int
+	file   string
+	record domain.ArticleRecord
+	err    error
+}
+
+func (s *transferService) ImportArticles(ctx context.Context, r io.ReaderAt, size int64, format string, opts ImportOptions) (*domain.ImportReport, error) {
+	var rows []importRow
+	var err error
+	switch format {
+	case domain.TransferFormatJSONL:
+		rows, err = readJSONL(io.NewSectionReader(r, 0, size))
+	case domain.TransferFormatMarkdown:
+		rows, err = readMarkdownZip(r, size)
+	default:
+		return nil, domain.ErrUnsupportedTransferFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &articleImport{
+		transferService: s,
+		opts:            opts,
+		report:          &domain.ImportReport{DryRun: opts.DryRun, Total: len(rows), Errors: []domain.ImportError{}},
+		authors:         make(map[uint]bool),
+		externalIDs:     make(map[string]bool),
+		slugs:           make(map[string]bool),
+	}
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+		if err := imp.batch(ctx, rows[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return imp.report, nil
+}
+
+// articleImport 一次导入的状态
+type articleImport struct {
+	*transferService
+	opts   ImportOptions
+	report *domain.ImportReport
+
+	authors     map[uint]bool   // 已确认存在的作者
+	externalIDs map[string]bool // 本次已导入的 作者:external_id，避免同一文件中重复
+	slugs       map[string]bool // 本次已分配的 slug
+}
+
+func (imp *articleImport) batch(ctx context.Context, rows []importRow) error {
+	var items []repository.ImportItem
+	var pending []importRow
+	var created []bool
+	for _, row := range rows {
+		if row.err != nil {
+			imp.fail(row, row.err)
+			continue
+		}
+		item, changed, err := imp.prepare(ctx, &row.record)
+		if err != nil {
+			imp.fail(row, err)
+			continue
+		}
+		if !changed {
+			imp.report.Unchanged++
+			continue
+		}
+		items = append(items, item)
+		pending = append(pending, row)
+		created = append(created, item.Article.ID == 0)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	errs, err := imp.articleRepo.ImportBatch(ctx, items, imp.opts.DryRun)
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		if errs[i] != nil {
+			imp.fail(pending[i], errs[i])
+			continue
+		}
+		if created[i] {
+			imp.report.Created++
+		} else {
+			imp.report.Updated++
+		}
+		if !imp.opts.DryRun {
+			if err := syncSearchIndex(ctx, imp.searchIndex, item.Article); err != nil {
+				logger.Error(ctx, "failed to index article", "article_id", item.Article.ID, logger.Err(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *articleImport) fail(row importRow, err error) {
+	imp.report.Failed++
+	importErr := domain.ImportError{
+		Row:        row.row,
+		File:       row.file,
+		ExternalID: row.record.ExternalID,
+		Error:      err.Error(),
+		Err:        err,
+	}
+	if e := errs.As(err); e != nil {
+		importErr.Code = e.Code
+	}
+	imp.report.Errors = append(imp.report.Errors, importErr)
+}
+
+// prepare 校验记录并生成待写入的文章，内容与已有文章相同时 changed 为 false
+func (imp *articleImport) prepare(ctx context.Context, record *domain.ArticleRecord) (repository.ImportItem, bool, error) {
+	var item repository.ImportItem
+
+	record.ExternalID = strings.TrimSpace(record.ExternalID)
+	if err := util.ValidateStruct(record); err != nil {
+		return item, false, errs.ErrValidation.Wrap(err)
+	}
+	externalID := record.ExternalID
+
+	// 普通作者导入时忽略记录中的 author_id，文章总是归属于自己
+	authorID := imp.opts.AuthorID
+	if imp.opts.AllowAuthor && record.AuthorID != 0 {
+		authorID = record.AuthorID
+	}
+	if authorID == 0 {
+		return item, false, domain.ErrAuthorIDRequired
+	}
+	if err := imp.checkAuthor(ctx, authorID); err != nil {
+		return item, false, err
+	}
+
+	key := fmt.Sprintf("%d:%s", authorID, externalID)
+	if imp.externalIDs[key] {
+		return item, false, domain.ErrDuplicateExternalID
+	}
+	imp.externalIDs[key] = true
+
+	existing, err := imp.findExisting(ctx, authorID, externalID)
+	if err != nil {
+		return item, false, err
+	}
+
+	article := &domain.Article{
+		Title:         record.Title,
+		Content:       record.Content,
+		ContentFormat: record.ContentFormat,
+		Status:        record.Status,
+		AuthorID:      authorID,
+		ExternalID:    &externalID,
+	}
+	if article.ContentFormat == "" {
+		article.ContentFormat = render.FormatPlain
+	}
+	article.Excerpt = render.Excerpt(article.ContentFormat, article.Content)
+
+	if article.Status == "" {
+		article.Status = domain.ArticleStatusPublished
+		if existing != nil {
+			article.Status = existing.Status
+		}
+	}
+
+	if existing != nil {
+		article.ID = existing.ID
+		article.CreatedAt = existing.CreatedAt
+		item.OldSlug = existing.Slug
+	} else if record.CreatedAt != nil {
+		article.CreatedAt = record.CreatedAt.UTC()
+	}
+
+	article.Slug = item.OldSlug
+	if record.Slug != "" && record.Slug != item.OldSlug || existing == nil {
+		if article.Slug, err = imp.resolveSlug(ctx, record.Slug, record.Title, article.ID); err != nil {
+			return item, false, err
+		}
+	}
+	imp.slugs[article.Slug] = true
+
+	item.Article = article
+	if existing != nil && existing.ExternalID != nil &&
+		existing.Title == article.Title && existing.Content == article.Content &&
+		existing.ContentFormat == article.ContentFormat && existing.Slug == article.Slug &&
+		existing.Status == article.Status {
+		return item, false, nil
+	}
+	return item, true, nil
+}
+
+func (imp *articleImport) checkAuthor(ctx context.Context, authorID uint) error {
+	if imp.authors[authorID] {
+		return nil
+	}
+	if _, err := imp.userRepo.FindByID(ctx, authorID); err != nil {
+		return domain.ErrAuthorNotFound
+	}
+	imp.authors[authorID] = true
+	return nil
+}
+
+// findExisting 查找要更新的文章
+// 没有匹配的外部标识时，匹配该作者下 slug 等于 external_id 且未设置外部标识的文章，
+// 与导出时以 slug 作为 external_id 的规则对应。
+func (imp *articleImport) findExisting(ctx context.Context, authorID uint, externalID string) (*domain.Article, error) {
+	article, err := imp.articleRepo.FindByExternalID(ctx, authorID, externalID)
+	if err == nil {
+		if article.DeletedAt.Valid {
+			return nil, domain.ErrArticleDeleted
+		}
+		return article, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	article, err = imp.articleRepo.FindBySlug(ctx, externalID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if article.AuthorID == authorID && article.ExternalID == nil {
+		return article, nil
+	}
+	return nil, nil
+}
+
+// resolveSlug 与 articleService.resolveSlug 相同，同时排除本次导入中已分配的 slug
+func (imp *articleImport) resolveSlug(ctx context.Context, requested, title string, articleID uint) (string, error) {
+	taken := func(candidate string) (bool, error) {
+		if imp.slugs[candidate] {
+			return true, nil
+		}
+		return imp.articleRepo.SlugTaken(ctx, candidate, articleID)
+	}
+
+	if requested == "" {
+		return slug.Unique(slug.Make(title), defaultSlug, taken)
+	}
+
+	if !slug.Valid(requested) {
+		return "", domain.ErrInvalidSlug
+	}
+	used, err := taken(requested)
+	if err != nil {
+		return "", err
+	}
+	if used {
+		return "", domain.ErrSlugInUse
+	}
+	return requested, nil
+}
+
+// readJSONL 读取 JSON Lines，跳过空行，行号从 1 开始
+func readJSONL(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+
+	var rows []importRow
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		row := importRow{row: line}
+		if err := json.Unmarshal(data, &row.record); err != nil {
+			row.err = fmt.Errorf("invalid json: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// readMarkdownZip 读取压缩包中的全部 .md 文件，按文件名排序
+func readMarkdownZip(r io.ReaderAt, size int64) ([]importRow, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, domain.ErrInvalidZipFile
+	}
+
+	var files []*zip.File
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() && strings.EqualFold(path.Ext(f.Name), ".md") {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	rows := make([]importRow, 0, len(files))
+	for i, f := range files {
+		row := importRow{row: i + 1, file: f.Name}
+		row.record.Content, row.err = readMarkdownFile(f, &row.record)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readMarkdownFile(f *zip.File, record *domain.ArticleRecord) (string, error) {
+	if f.UncompressedSize64 > maxImportLineSize {
+		return "", domain.ErrFileTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportLineSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxImportLineSize {
+		return "", domain.ErrFileTooLarge
+	}
+	return frontmatter.Parse(data, record)
+}
+
+// articleMarkdown 文章导出为带 YAML front matter 的 Markdown
+func articleMarkdown(article *domain.Article) ([]byte, error) {
+	record := domain.NewArticleRecord(article)
+	return frontmatter.Format(record, record.Content)
+}
+
+// articleFileName 文章在导出压缩包中的文件名
+func articleFileName(article *domain.Article) string {
+	if article.Slug == "" {
+		return fmt.Sprintf("articles/%d.md", article.ID)
+	}
+	return fmt.Sprintf("articles/%d-%s.md", article.ID, article.Slug)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/storage"
+)
+
+type TrashService interface {
+	// ListDeletedArticles 列出回收站中的文章，authorID 为 0 时列出全部（管理员）
+	ListDeletedArticles(ctx context.Context, authorID uint, page, pageSize int) ([]domain.Article, int64, error)
+	ListDeletedUsers(ctx context.Context, page, pageSize int) ([]domain.User, int64, error)
+	RestoreArticle(ctx context.Context, id, userID uint) error
+	RestoreUser(ctx context.Context, id uint) error
+	Purge(ctx context.Context, before time.Time) (articles, users int, err error)
+}
+
+type trashService struct {
+	trashRepo   repository.TrashRepository
+	articleRepo repository.ArticleRepository
+	userRepo    repository.UserRepository
+	searchIndex search.SearchIndex
+	store       storage.Storage
+}
+
+func NewTrashService(trashRepo repository.TrashRepository, articleRepo repository.ArticleRepository, userRepo repository.UserRepository, searchIndex search.SearchIndex, store storage.Storage) TrashService {
+	return &trashService{
+		trashRepo:   trashRepo,
+		articleRepo: articleRepo,
+		userRepo:    userRepo,
+		searchIndex: searchIndex,
+		store:       store,
+	}
+}
+
+func (s *trashService) ListDeletedArticles(ctx context.Context, authorID uint, page, pageSize int) ([]domain.Article, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+	return s.articleRepo.FindDeleted(ctx, authorID, pageSize, (page-1)*pageSize)
+}
+
+func (s *trashService) ListDeletedUsers(ctx context.Context, page, pageSize int) ([]domain.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	pageSize = normalizePageSize(pageSize)
+	return s.userRepo.FindDeleted(ctx, pageSize, (page-1)*pageSize)
+}
+
+// RestoreArticle 恢复文章，作者本人或管理员可以操作
+// 作者已被删除时需要先恢复作者。
+func (s *trashService) RestoreArticle(ctx context.Context, id, userID uint) error {
+	article, err := s.articleRepo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return domain.ErrArticleNotFound
+	}
+
+	if article.AuthorID != userID {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil || !user.IsAdmin() {
+			return domain.ErrPermissionDenied
+		}
+	}
+	if _, err := s.userRepo.FindByID(ctx, article.AuthorID); err != nil {
+		return domain.ErrAuthorDeleted
+	}
+
+	if err := s.articleRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+	s.reindex(ctx, id)
+	return nil
+}
+
+// RestoreUser 恢复用户及随用户一起删除的文章
+func (s *trashService) RestoreUser(ctx context.Context, id uint) error {
+	if _, err := s.userRepo.FindDeletedByID(ctx, id); err != nil {
+		return domain.ErrUserNotFound
+	}
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += reindexBatchSize {
+		articles, _, err := s.articleRepo.FindByAuthorID(ctx, id, false, reindexBatchSize, offset, nil)
+		if err != nil {
+			return err
+		}
+		for i := range articles {
+			if err := s.searchIndex.Index(ctx, &articles[i]); err != nil {
+				logger.Error(ctx, "failed to index article", "article_id", articles[i].ID, logger.Err(err))
+			}
+		}
+		if len(articles) < reindexBatchSize {
+			return nil
+		}
+	}
+}
+
+// Purge 彻底删除 before 之前进入回收站的文章和用户，并清理搜索索引与文件
+func (s *trashService) Purge(ctx context.Context, before time.Time) (int, int, error) {
+	articles, err := s.trashRepo.PurgeArticles(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+	s.cleanup(ctx, articles)
+
+	users, err := s.trashRepo.PurgeUsers(ctx, before)
+	if err != nil {
+		return len(articles.ArticleIDs), 0, err
+	}
+	s.cleanup(ctx, users)
+
+	return len(articles.ArticleIDs) + len(users.ArticleIDs), len(users.UserIDs), nil
+}
+
+// cleanup 清理数据库之外的数据，失败时只记录日志
+func (s *trashService) cleanup(ctx context.Context, result *repository.PurgeResult) {
+	for _, id := range result.ArticleIDs {
+		if err := s.searchIndex.Remove(ctx, id); err != nil {
+			logger.Error(ctx, "failed to remove article from search index", "article_id", id, logger.Err(err))
+		}
+	}
+	for _, key := range result.StorageKeys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Error(ctx, "failed to delete file", "key", key, logger.Err(err))
+		}
+	}
+}
+
+func (s *trashService) reindex(ctx context.Context, id uint) {
+	article, err := s.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(ctx, "failed to load restored article", "article_id", id, logger.Err(err))
+		return
+	}
+	if err := syncSearchIndex(ctx, s.searchIndex, article); err != nil {
+		logger.Error(ctx, "failed to index article", "article_id", id, logger.Err(err))
+	}
+}
+
+// TrashPurger 定期彻底删除回收站中超过保留期的数据
+type TrashPurger struct {
+	trashService TrashService
+	retention    time.Duration
+	interval     time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewTrashPurger(trashService TrashService, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		trashService: trashService,
+		retention:    retention,
+		interval:     interval,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start 启动后台清理，启动时立即执行一次
+func (p *TrashPurger) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.purge()
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台清理
+func (p *TrashPurger) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+func (p *TrashPurger) purge() {
+	ctx := context.Background()
+	articles, users, err := p.trashService.Purge(ctx, time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		logger.Error(ctx, "failed to purge trash", logger.Err(err))
+		return
+	}
+	if articles > 0 || users > 0 {
+		logger.Info(ctx, "purged trash", "articles", articles, "users", users)
+	}
+}
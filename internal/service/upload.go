@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+	"slices"
+
+	"github.com/Anning01/user-management/internal/domain"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// sniffLength 判断文件类型时读取的字节数
+const sniffLength = 3072
+
+// UploadLimits 上传文件的大小与类型限制
+type UploadLimits struct {
+	MaxSize      int64    // 字节
+	AllowedTypes []string // 允许的 MIME 类型
+}
+
+// sniffedFile 已识别类型的上传文件，Reader 包含完整内容
+type sniffedFile struct {
+	Reader      io.Reader
+	ContentType string
+	Extension   string
+}
+
+// checkUpload 校验文件大小，并根据文件内容（而非客户端声明的类型）判断 MIME 类型
+func checkUpload(r io.Reader, size int64, limits UploadLimits) (*sniffedFile, error) {
+	if size > limits.MaxSize {
+		return nil, domain.ErrFileTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	mt := mimetype.Detect(head)
+	contentType := mt.String()
+	if !slices.ContainsFunc(limits.AllowedTypes, mt.Is) {
+		return nil, domain.ErrFileTypeNotAllowed
+	}
+
+	return &sniffedFile{
+		Reader:      io.MultiReader(bytes.NewReader(head), r),
+		ContentType: contentType,
+		Extension:   mt.Extension(),
+	}, nil
+}
+
+// newStorageKey 生成随机的存储 key，如 attachments/12/3f9a…c1.png
+func newStorageKey(prefix, ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return path.Join(prefix, hex.EncodeToString(buf)+ext), nil
+}
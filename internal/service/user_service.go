@@ -1,79 +1,161 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/internal/metrics"
 	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/pkg/logger"
 	"github.com/Anning01/user-management/pkg/security"
+
+	"gorm.io/gorm"
 )
 
 type UserService interface {
-	Register(user *domain.User) error
-	Login(email, password string) (*domain.User, error)
-	GetUserByID(id uint) (*domain.User, error)
-	UpdateUser(user *domain.User) error
-	DeleteUser(id uint) error
+	Register(ctx context.Context, user *domain.User) error
+	Login(ctx context.Context, email, password string) (*domain.User, error)
+	GetUserByID(ctx context.Context, id uint) (*domain.User, error)
+	UpdateUser(ctx context.Context, user *domain.User) error
+	DeleteUser(ctx context.Context, id uint) error
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	articleRepo repository.ArticleRepository
+	searchIndex search.SearchIndex
+	txManager   repository.TxManager
+	metrics     *metrics.Metrics
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo}
+// NewUserService 创建用户服务，m 为 nil 时不记录登录与注册指标
+func NewUserService(userRepo repository.UserRepository, articleRepo repository.ArticleRepository, searchIndex search.SearchIndex, txManager repository.TxManager, m *metrics.Metrics) UserService {
+	return &userService{userRepo, articleRepo, searchIndex, txManager, m}
 }
 
-func (s *userService) Register(user *domain.User) error {
-	// 检查用户名是否已存在
-	existingUser, _ := s.userRepo.FindByUsername(user.Username)
-	if existingUser != nil {
-		return errors.New("username already exists")
-	}
-
-	// 检查邮箱是否已存在
-	existingUser, _ = s.userRepo.FindByEmail(user.Email)
-	if existingUser != nil {
-		return errors.New("email already exists")
+func (s *userService) Register(ctx context.Context, user *domain.User) error {
+	// 注销后匿名化的账号使用保留前缀
+	if strings.HasPrefix(user.Username, domain.DeletedUserPrefix) {
+		return domain.ErrUsernameReserved
 	}
 
-	// 密码加密
+	// 密码加密放在事务之外，避免长时间占用连接
 	hashedPassword, err := security.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
 	user.Password = hashedPassword
 
-	return s.userRepo.Create(user)
+	// 注册的用户一律为普通用户，管理员需在数据库中指定
+	user.Role = domain.RoleUser
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkAvailable(ctx, user); err != nil {
+			return err
+		}
+		return s.userRepo.Create(ctx, user)
+	})
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		// 并发注册时先检查后写入仍可能冲突，由唯一索引兜底，事务回滚后再确认冲突的字段；
+		// 与回收站中的用户冲突时查不到对应记录
+		if err := s.checkAvailable(ctx, user); err != nil {
+			return err
+		}
+		return domain.ErrUsernameOrEmailExists
+	}
+	if err == nil {
+		s.metrics.Registered()
+	}
+	return err
+}
+
+// checkAvailable 检查用户名与邮箱是否已被占用，查询失败时返回错误而不是视为可用
+func (s *userService) checkAvailable(ctx context.Context, user *domain.User) error {
+	_, err := s.userRepo.FindByUsername(ctx, user.Username)
+	if err == nil {
+		return domain.ErrUsernameExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	_, err = s.userRepo.FindByEmail(ctx, user.Email)
+	if err == nil {
+		return domain.ErrEmailExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
 }
 
-func (s *userService) Login(email, password string) (*domain.User, error) {
-	user, err := s.userRepo.FindByEmail(email)
+func (s *userService) Login(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		s.metrics.Login(false)
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	if err := security.CheckPasswordHash(password, user.Password); err != nil {
-		return nil, errors.New("invalid email or password")
+		s.metrics.Login(false)
+		return nil, domain.ErrInvalidCredentials
 	}
 
+	s.metrics.Login(true)
 	return user, nil
 }
 
-func (s *userService) GetUserByID(id uint) (*domain.User, error) {
-	return s.userRepo.FindByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, err
 }
 
-func (s *userService) UpdateUser(user *domain.User) error {
+func (s *userService) UpdateUser(ctx context.Context, user *domain.User) error {
 	// 如果更新了邮箱，检查是否已被其他用户使用
-	existingUser, _ := s.userRepo.FindByEmail(user.Email)
-	if existingUser != nil && existingUser.ID != user.ID {
-		return errors.New("email already exists")
+	existingUser, err := s.userRepo.FindByEmail(ctx, user.Email)
+	if err == nil && existingUser.ID != user.ID {
+		return domain.ErrEmailExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
 	}
 
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return domain.ErrEmailExists
+		}
+		return err
+	}
+	return nil
 }
 
-func (s *userService) DeleteUser(id uint) error {
-	return s.userRepo.Delete(id)
+// DeleteUser 软删除用户及其文章，并将文章从搜索索引中移除，恢复用户时重新索引
+// 文章与用户使用相同的删除时间，恢复用户时据此只恢复随用户一起删除的文章。
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	now := time.Now().UTC()
+	var articleIDs []uint
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, id, now); err != nil {
+			return err
+		}
+		var err error
+		articleIDs, err = s.articleRepo.DeleteByAuthor(ctx, id, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, articleID := range articleIDs {
+		if err := s.searchIndex.Remove(ctx, articleID); err != nil {
+			logger.Error(ctx, "failed to remove article from search index", "article_id", articleID, logger.Err(err))
+		}
+	}
+	return nil
 }
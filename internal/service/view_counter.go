@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/pkg/logger"
+)
+
+// ViewCounter 在内存中缓冲文章浏览量，并定期批量写入数据库
+// 同一访客在去重窗口内多次浏览同一篇文章只计一次。
+type ViewCounter struct {
+	repo          repository.EngagementRepository
+	dedupWindow   time.Duration
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[uint]int64
+	seen    map[string]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewViewCounter(repo repository.EngagementRepository, flushInterval, dedupWindow time.Duration) *ViewCounter {
+	return &ViewCounter{
+		repo:          repo,
+		dedupWindow:   dedupWindow,
+		flushInterval: flushInterval,
+		pending:       make(map[uint]int64),
+		seen:          make(map[string]time.Time),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Record 记录一次浏览，viewer 为访客标识（用户ID或客户端指纹）
+func (v *ViewCounter) Record(articleID uint, viewer string) {
+	key := fmt.Sprintf("%d:%s", articleID, viewer)
+	now := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if last, ok := v.seen[key]; ok && now.Sub(last) < v.dedupWindow {
+		return
+	}
+	v.seen[key] = now
+	v.pending[articleID]++
+}
+
+// Start 启动后台定期刷写
+func (v *ViewCounter) Start() {
+	go func() {
+		defer close(v.done)
+
+		ticker := time.NewTicker(v.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := v.Flush(); err != nil {
+					logger.Error(context.Background(), "failed to flush view counts", logger.Err(err))
+				}
+			case <-v.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台刷写，并把剩余的浏览量写入数据库
+func (v *ViewCounter) Stop() error {
+	close(v.stop)
+	<-v.done
+	return v.Flush()
+}
+
+// Flush 将缓冲的浏览量写入数据库，并清理过期的去重记录
+// 写入失败时浏览量会放回缓冲区，等待下次重试。
+func (v *ViewCounter) Flush() error {
+	v.mu.Lock()
+	counts := v.pending
+	v.pending = make(map[uint]int64)
+
+	cutoff := time.Now().Add(-v.dedupWindow)
+	for key, last := range v.seen {
+		if last.Before(cutoff) {
+			delete(v.seen, key)
+		}
+	}
+	v.mu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	if err := v.repo.IncrementViewCounts(context.Background(), counts); err != nil {
+		v.mu.Lock()
+		for id, n := range counts {
+			v.pending[id] += n
+		}
+		v.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/api/middleware"
+	"github.com/Anning01/user-management/internal/domain"
+)
+
+// Client 访问测试服务的 HTTP 客户端，设置 Token 后自动携带认证头，不跟随重定向
+type Client struct {
+	BaseURL string
+	Token   string
+	// Header 每个请求都附带的请求头，例如 Accept-Language
+	Header http.Header
+	http   *http.Client
+}
+
+// Response 读取完毕的响应
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Client 返回未认证的客户端
+func (s *Server) Client() *Client {
+	return &Client{
+		BaseURL: s.URL,
+		http: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Login 以指定用户登录，返回携带令牌的客户端
+func (s *Server) Login(t testing.TB, user *domain.User) *Client {
+	t.Helper()
+
+	c := s.Client()
+	var body struct {
+		Token string `json:"token"`
+	}
+	c.Post(t, "/api/v1/users/login", map[string]string{
+		"email":    user.Email,
+		"password": user.Password,
+	}).Expect(t, http.StatusOK).Decode(t, &body)
+	c.Token = body.Token
+	return c
+}
+
+// Get 发送 GET 请求
+func (c *Client) Get(t testing.TB, path string) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodGet, path, nil, nil)
+}
+
+// Post 发送 JSON 请求体的 POST 请求，body 为 nil 时不带请求体
+func (c *Client) Post(t testing.TB, path string, body any) *Response {
+	t.Helper()
+	return c.sendJSON(t, http.MethodPost, path, body)
+}
+
+// Put 发送 JSON 请求体的 PUT 请求
+func (c *Client) Put(t testing.TB, path string, body any) *Response {
+	t.Helper()
+	return c.sendJSON(t, http.MethodPut, path, body)
+}
+
+// Delete 发送 DELETE 请求
+func (c *Client) Delete(t testing.TB, path string) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodDelete, path, nil, nil)
+}
+
+// Upload 以 multipart/form-data 上传单个文件
+func (c *Client) Upload(t testing.TB, method, path, field, filename, contentType string, data []byte) *Response {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="`+field+`"; filename="`+filename+`"`)
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatalf("create multipart part: %v", err)
+	}
+	part.Write(data)
+	w.Close()
+
+	return c.Do(t, method, path, &buf, http.Header{"Content-Type": {w.FormDataContentType()}})
+}
+
+func (c *Client) sendJSON(t testing.TB, method, path string, body any) *Response {
+	t.Helper()
+
+	if body == nil {
+		return c.Do(t, method, path, nil, nil)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("encode request body: %v", err)
+	}
+	return c.Do(t, method, path, bytes.NewReader(data), http.Header{"Content-Type": {"application/json"}})
+}
+
+// Do 发送请求并读取完整响应，header 中的值会覆盖默认请求头
+func (c *Client) Do(t testing.TB, method, path string, body io.Reader, header http.Header) *Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, c.BaseURL+path, body)
+	if err != nil {
+		t.Fatalf("new request %s %s: %v", method, path, err)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response of %s %s: %v", method, path, err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+}
+
+// Expect 断言状态码，不符时输出响应体并终止测试
+func (r *Response) Expect(t testing.TB, status int) *Response {
+	t.Helper()
+	if r.StatusCode != status {
+		t.Fatalf("expected status %d, got %d: %s", status, r.StatusCode, r.Body)
+	}
+	return r
+}
+
+// Decode 将 JSON 响应体解析到 v
+func (r *Response) Decode(t testing.TB, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode response %s: %v", r.Body, err)
+	}
+}
+
+// JSON 将响应体解析为 map
+func (r *Response) JSON(t testing.TB) map[string]any {
+	t.Helper()
+	var m map[string]any
+	r.Decode(t, &m)
+	return m
+}
+
+// Problem 解析 application/problem+json 错误响应
+func (r *Response) Problem(t testing.TB) middleware.Problem {
+	t.Helper()
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, middleware.ProblemContentType) {
+		t.Fatalf("expected %s response, got %q: %s", middleware.ProblemContentType, ct, r.Body)
+	}
+	var problem middleware.Problem
+	r.Decode(t, &problem)
+	if problem.Status != r.StatusCode || problem.Code == "" {
+		t.Fatalf("malformed problem response: %s", r.Body)
+	}
+	return problem
+}
+
+// Error 返回错误响应的 detail
+func (r *Response) Error(t testing.TB) string {
+	t.Helper()
+	return r.Problem(t).Detail
+}
+
+// ExpectError 断言状态码与错误信息
+func (r *Response) ExpectError(t testing.TB, status int, message string) {
+	t.Helper()
+	r.Expect(t, status)
+	if got := r.Error(t); got != message {
+		t.Fatalf("expected error %q, got %q", message, got)
+	}
+}
+
+// ExpectCode 断言状态码与错误码
+func (r *Response) ExpectCode(t testing.TB, status int, code string) middleware.Problem {
+	t.Helper()
+	r.Expect(t, status)
+	problem := r.Problem(t)
+	if problem.Code != code {
+		t.Fatalf("expected error code %q, got %q", code, problem.Code)
+	}
+	return problem
+}
@@ -0,0 +1,106 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Anning01/user-management/internal/domain"
+)
+
+// DefaultPassword 测试用户的默认密码
+const DefaultPassword = "password123"
+
+// UserOption 修改待创建用户的字段
+type UserOption func(*domain.User)
+
+// ArticleOption 修改待创建文章的字段
+type ArticleOption func(*domain.Article)
+
+// WithUsername 指定用户名，邮箱随之生成
+func WithUsername(username string) UserOption {
+	return func(u *domain.User) {
+		u.Username = username
+		u.Email = username + "@example.com"
+	}
+}
+
+// AsAdmin 创建管理员
+func AsAdmin() UserOption {
+	return func(u *domain.User) {
+		u.Role = domain.RoleAdmin
+	}
+}
+
+// WithTitle 指定文章标题
+func WithTitle(title string) ArticleOption {
+	return func(a *domain.Article) {
+		a.Title = title
+	}
+}
+
+// WithContent 指定文章正文
+func WithContent(content string) ArticleOption {
+	return func(a *domain.Article) {
+		a.Content = content
+	}
+}
+
+// AsDraft 创建草稿
+func AsDraft() ArticleOption {
+	return func(a *domain.Article) {
+		a.Status = domain.ArticleStatusDraft
+	}
+}
+
+// CreateUser 通过用户服务注册用户，返回的 Password 为明文密码，便于登录
+func (s *Server) CreateUser(t testing.TB, opts ...UserOption) *domain.User {
+	t.Helper()
+
+	s.seq++
+	user := &domain.User{
+		Username: fmt.Sprintf("user%d", s.seq),
+		Email:    fmt.Sprintf("user%d@example.com", s.seq),
+		FullName: fmt.Sprintf("Test User %d", s.seq),
+		Role:     domain.RoleUser,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+	role := user.Role
+
+	user.Password = DefaultPassword
+	if err := s.Users.Register(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", user.Username, err)
+	}
+	// 注册接口总是创建普通用户，管理员直接在数据库中指定
+	if role != domain.RoleUser {
+		if err := s.DB.Model(user).Update("role", role).Error; err != nil {
+			t.Fatalf("set role of %s: %v", user.Username, err)
+		}
+		user.Role = role
+	}
+	user.Password = DefaultPassword
+	return user
+}
+
+// CreateArticle 通过文章服务创建文章，默认为已发布状态
+func (s *Server) CreateArticle(t testing.TB, author *domain.User, opts ...ArticleOption) *domain.Article {
+	t.Helper()
+
+	s.seq++
+	article := &domain.Article{
+		Title:    fmt.Sprintf("Test Article %d", s.seq),
+		Content:  fmt.Sprintf("This is the content of test article %d.", s.seq),
+		AuthorID: author.ID,
+		Status:   domain.ArticleStatusPublished,
+	}
+	for _, opt := range opts {
+		opt(article)
+	}
+
+	if err := s.Articles.CreateArticle(context.Background(), article); err != nil {
+		t.Fatalf("create article %q: %v", article.Title, err)
+	}
+	return article
+}
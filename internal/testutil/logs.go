@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/Anning01/user-management/pkg/logger"
+)
+
+// LogBuffer 收集全局日志的 JSON 输出，可被服务端的多个 goroutine 并发写入
+type LogBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// Entries 解析已写入的日志，每行一条
+func (b *LogBuffer) Entries(t testing.TB) []map[string]any {
+	t.Helper()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(b.buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Find 返回第一条 msg 相同的日志，不存在时终止测试
+func (b *LogBuffer) Find(t testing.TB, msg string) map[string]any {
+	t.Helper()
+
+	entries := b.FindAll(t, msg)
+	if len(entries) == 0 {
+		t.Fatalf("no log entry with msg %q", msg)
+	}
+	return entries[0]
+}
+
+// FindAll 返回全部 msg 相同的日志
+func (b *LogBuffer) FindAll(t testing.TB, msg string) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for _, entry := range b.Entries(t) {
+		if entry["msg"] == msg {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// CaptureLogs 将全局日志改为 debug 级别的 JSON 输出并写入返回的缓冲区，测试结束时恢复。
+// 全局日志为所有测试共享，使用它的测试不能并行运行。
+func CaptureLogs(t testing.TB) *LogBuffer {
+	t.Helper()
+
+	buf := &LogBuffer{}
+	if err := logger.Init(logger.Options{Level: "debug", Format: logger.FormatJSON, Output: buf}); err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+	t.Cleanup(func() {
+		logCfg := DefaultConfig().Log
+		_ = logger.Init(logger.Options{Level: logCfg.Level, Format: logCfg.Format})
+	})
+	return buf
+}
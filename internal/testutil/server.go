@@ -0,0 +1,221 @@
+// Package testutil 提供集成测试使用的进程内服务、测试数据构造与 HTTP 客户端
+package testutil
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/internal/api"
+	"github.com/Anning01/user-management/internal/api/handlers"
+	"github.com/Anning01/user-management/internal/config"
+	"github.com/Anning01/user-management/internal/i18n"
+	"github.com/Anning01/user-management/internal/metrics"
+	"github.com/Anning01/user-management/internal/repository"
+	"github.com/Anning01/user-management/internal/search"
+	"github.com/Anning01/user-management/internal/service"
+	"github.com/Anning01/user-management/migrations"
+	"github.com/Anning01/user-management/pkg/logger"
+	"github.com/Anning01/user-management/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var initOnce sync.Once
+
+// Server 进程内启动的完整 API 服务，每个实例使用独立的内存 SQLite 数据库
+type Server struct {
+	*httptest.Server
+
+	Config *config.Config
+	DB     *gorm.DB
+	Store  storage.Storage
+	Signer *storage.Signer
+
+	// 后台任务不会自动运行，测试中直接调用对应的服务方法
+	Users    service.UserService
+	Articles service.ArticleService
+	Views    *service.ViewCounter
+	Trash    service.TrashService
+	Privacy  service.PrivacyService
+	// Metrics 未启用指标时为 nil，配置了管理端口时可通过 Metrics.Handler() 读取指标
+	Metrics *metrics.Metrics
+
+	seq int
+}
+
+// DefaultConfig 测试使用的配置，与 config.Load 的默认值保持一致
+func DefaultConfig() *config.Config {
+	return &config.Config{
+		Database: config.DatabaseConfig{Driver: repository.DriverSQLite, Name: ":memory:", QueryTimeout: 10, SlowThreshold: 200},
+		JWT:      config.JWTConfig{SecretKey: "test-secret", ExpirationHours: 1},
+		Search:   config.SearchConfig{Backend: search.BackendMemory},
+		Views:    config.ViewsConfig{FlushInterval: 10, DedupWindow: 30},
+		Feed: config.FeedConfig{
+			Title:       "User Management",
+			Description: "Latest articles",
+			ItemCount:   20,
+		},
+		Storage: config.StorageConfig{Driver: "local", URLExpiry: 60, SigningKey: "test-signing-key"},
+		Upload: config.UploadConfig{
+			MaxSize:         10,
+			AllowedTypes:    []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf", "text/plain"},
+			AvatarMaxSize:   5,
+			AvatarThumbSize: 128,
+			ImportMaxSize:   50,
+		},
+		Trash:   config.TrashConfig{RetentionDays: 30, PurgeInterval: 24},
+		Privacy: config.PrivacyConfig{CoolingOffDays: 14, ExportExpiry: 72, CheckInterval: 60},
+		I18n:    config.I18nConfig{DefaultLocale: "en"},
+		Log: config.LogConfig{
+			Level:  "info",
+			Format: "text",
+			Access: config.AccessLogConfig{Enabled: true, SampleRate: 1, SkipPaths: []string{"/health"}},
+		},
+		Metrics: config.MetricsConfig{Enabled: true},
+	}
+}
+
+// NewServer 按 cmd/api 的方式组装服务并启动，configure 可在启动前修改配置。
+// 测试结束时自动关闭服务与数据库连接。
+func NewServer(t testing.TB, configure ...func(*config.Config)) *Server {
+	t.Helper()
+
+	initOnce.Do(func() {
+		logCfg := DefaultConfig().Log
+		if err := logger.Init(logger.Options{Level: logCfg.Level, Format: logCfg.Format}); err != nil {
+			t.Fatalf("logger: %v", err)
+		}
+		gin.SetMode(gin.TestMode)
+	})
+
+	cfg := DefaultConfig()
+	cfg.Storage.LocalDir = t.TempDir()
+	for _, fn := range configure {
+		fn(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	db, err := repository.NewDBConnection(&cfg.Database)
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	db.Logger = gormlogger.Discard
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	var m *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		m = metrics.New()
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatalf("database handle: %v", err)
+		}
+		if err := m.RegisterDB(sqlDB, cfg.Database.Name); err != nil {
+			t.Fatalf("register database metrics: %v", err)
+		}
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	articleRepo := repository.NewArticleRepository(db)
+	contributorRepo := repository.NewContributorRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	engagementRepo := repository.NewEngagementRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	privacyRepo := repository.NewPrivacyRepository(db)
+	txManager := repository.NewTxManager(db)
+
+	searchIndex, err := search.NewIndex(cfg.Search.Backend, db)
+	if err != nil {
+		t.Fatalf("search index: %v", err)
+	}
+
+	signer := storage.NewSigner(cfg.Storage.SigningKey)
+	store, err := storage.New(storage.Options{
+		Driver:    cfg.Storage.Driver,
+		LocalDir:  cfg.Storage.LocalDir,
+		PublicURL: cfg.Storage.PublicURL,
+		Signer:    signer,
+		Endpoint:  cfg.Storage.S3.Endpoint,
+		Region:    cfg.Storage.S3.Region,
+		Bucket:    cfg.Storage.S3.Bucket,
+		AccessKey: cfg.Storage.S3.AccessKey,
+		SecretKey: cfg.Storage.S3.SecretKey,
+		UseSSL:    cfg.Storage.S3.UseSSL,
+	})
+	if err != nil {
+		t.Fatalf("storage: %v", err)
+	}
+
+	userService := service.NewUserService(userRepo, articleRepo, searchIndex, txManager, m)
+	articleService := service.NewArticleService(articleRepo, contributorRepo, seriesRepo, userRepo, txManager, searchIndex)
+	seriesService := service.NewSeriesService(seriesRepo, articleRepo, contributorRepo, userRepo)
+	commentService := service.NewCommentService(commentRepo, articleRepo, contributorRepo, userRepo, txManager)
+	followService := service.NewFollowService(followRepo, userRepo)
+
+	urlExpiry := cfg.Storage.URLExpiry * time.Minute
+	maxUploadSize := cfg.Upload.MaxSize << 20
+	maxAvatarSize := cfg.Upload.AvatarMaxSize << 20
+	attachmentService := service.NewAttachmentService(attachmentRepo, articleRepo, contributorRepo, store,
+		service.UploadLimits{MaxSize: maxUploadSize, AllowedTypes: cfg.Upload.AllowedTypes}, urlExpiry)
+	avatarService := service.NewAvatarService(userRepo, store,
+		service.UploadLimits{MaxSize: maxAvatarSize, AllowedTypes: service.AvatarTypes}, cfg.Upload.AvatarThumbSize, urlExpiry)
+
+	viewCounter := service.NewViewCounter(engagementRepo, cfg.Views.FlushInterval*time.Second, cfg.Views.DedupWindow*time.Minute)
+	engagementService := service.NewEngagementService(engagementRepo, articleRepo, viewCounter)
+	trashService := service.NewTrashService(trashRepo, articleRepo, userRepo, searchIndex, store)
+	retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+	privacyService := service.NewPrivacyService(privacyRepo, userRepo, searchIndex, store,
+		time.Duration(cfg.Privacy.CoolingOffDays)*24*time.Hour, cfg.Privacy.ExportExpiry*time.Hour, urlExpiry)
+	transferService := service.NewTransferService(articleRepo, userRepo, searchIndex)
+
+	bundle, err := i18n.NewBundle(cfg.I18n.DefaultLocale, cfg.I18n.Dir)
+	if err != nil {
+		t.Fatalf("message catalog: %v", err)
+	}
+
+	r := gin.New()
+	api.SetupRoutes(r,
+		handlers.NewUserHandler(userService, followService, &cfg.JWT),
+		handlers.NewArticleHandler(articleService, engagementService),
+		handlers.NewCommentHandler(commentService),
+		handlers.NewEngagementHandler(engagementService),
+		handlers.NewFollowHandler(followService, userService),
+		handlers.NewFeedHandler(articleService, userService, &cfg.Feed),
+		handlers.NewUploadHandler(attachmentService, avatarService, store, signer, maxUploadSize, maxAvatarSize),
+		handlers.NewTrashHandler(trashService, retention),
+		handlers.NewPrivacyHandler(privacyService),
+		handlers.NewTransferHandler(transferService, cfg.Upload.ImportMaxSize<<20),
+		handlers.NewSeriesHandler(seriesService),
+		userService, &cfg.JWT, &cfg.Log.Access, &cfg.Metrics, m, bundle)
+
+	s := &Server{
+		Server:   httptest.NewServer(r),
+		Config:   cfg,
+		DB:       db,
+		Store:    store,
+		Signer:   signer,
+		Users:    userService,
+		Articles: articleService,
+		Views:    viewCounter,
+		Trash:    trashService,
+		Privacy:  privacyService,
+		Metrics:  m,
+	}
+	t.Cleanup(func() {
+		s.Close()
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return s
+}
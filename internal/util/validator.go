@@ -1,16 +1,133 @@
 package util
 
 import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
 )
 
-var validate *validator.Validate
+// FieldError 单个字段的校验错误
+// Field 为 JSON 字段名，嵌套字段以 . 连接；Rule 与 Param 为校验规则及其参数，Message 为翻译后的提示。
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var (
+	validate *validator.Validate
+	uni      *ut.UniversalTranslator
+)
+
+var (
+	// usernamePattern 用户名只允许字母、数字、下划线和连字符
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// htmlTagPattern 匹配 HTML 标签、注释与声明
+	htmlTagPattern = regexp.MustCompile(`<\s*/?\s*[A-Za-z!][^>]*>`)
+)
+
+// 自定义规则的提示文案
+var customTranslations = map[string]map[string]string{
+	"en": {
+		"username": "{0} can only contain letters, numbers, underscores and hyphens",
+		"nohtml":   "{0} must not contain HTML tags",
+	},
+	"zh": {
+		"username": "{0}只能包含字母、数字、下划线和连字符",
+		"nohtml":   "{0}不能包含HTML标签",
+	},
+}
 
 func init() {
 	validate = validator.New()
+	// 错误中的字段名使用 JSON 字段名，与请求体保持一致
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	mustRegister(validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernamePattern.MatchString(fl.Field().String())
+	}))
+	mustRegister(validate.RegisterValidation("nohtml", func(fl validator.FieldLevel) bool {
+		return !htmlTagPattern.MatchString(fl.Field().String())
+	}))
+
+	enLocale := en.New()
+	uni = ut.New(enLocale, enLocale, zh.New())
+	enTrans, _ := uni.GetTranslator("en")
+	zhTrans, _ := uni.GetTranslator("zh")
+	mustRegister(enTranslations.RegisterDefaultTranslations(validate, enTrans))
+	mustRegister(zhTranslations.RegisterDefaultTranslations(validate, zhTrans))
+	for _, trans := range []ut.Translator{enTrans, zhTrans} {
+		for tag, text := range customTranslations[trans.Locale()] {
+			mustRegister(registerTranslation(trans, tag, text))
+		}
+	}
+}
+
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// registerTranslation 注册自定义规则的提示文案，{0} 为字段名
+func registerTranslation(trans ut.Translator, tag, text string) error {
+	return validate.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+		return t.Add(tag, text, true)
+	}, func(t ut.Translator, fe validator.FieldError) string {
+		msg, err := t.T(tag, fe.Field())
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	})
 }
 
 // ValidateStruct 验证结构体
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
+
+// FieldErrors 将校验错误转换为字段错误列表，提示按 locale 翻译，例如 zh-CN 或 en，不支持的语言使用英文。
+// err 中不包含校验错误时返回 nil。
+func FieldErrors(err error, locale string) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	primary, _, _ := strings.Cut(locale, "-")
+	trans, _ := uni.FindTranslator(locale, strings.ToLower(primary))
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return fields
+}
+
+// fieldPath 去掉命名空间开头的结构体名，例如 registerRequest.username 返回 username
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
@@ -2,6 +2,8 @@ package migrations
 
 import (
 	"github.com/Anning01/user-management/internal/domain"
+	"github.com/Anning01/user-management/pkg/render"
+	"github.com/Anning01/user-management/pkg/slug"
 
 	"github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/gorm"
@@ -27,6 +29,281 @@ func Migrate(db *gorm.DB) error {
 				return tx.Migrator().DropTable(&domain.Article{})
 			},
 		},
+		{
+			// 文章全文索引（仅 MySQL，使用 ngram 解析器以支持中文分词）
+			ID: "20250101000001",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Dialector.Name() != "mysql" {
+					return nil
+				}
+				return tx.Exec("ALTER TABLE articles ADD FULLTEXT INDEX idx_articles_fulltext (title, content) WITH PARSER ngram").Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if tx.Dialector.Name() != "mysql" {
+					return nil
+				}
+				return tx.Exec("ALTER TABLE articles DROP INDEX idx_articles_fulltext").Error
+			},
+		},
+		{
+			// 游标分页使用的 (created_at, id) 与 (author_id, created_at, id) 复合索引
+			ID: "20250101000002",
+			Migrate: func(tx *gorm.DB) error {
+				for _, name := range []string{"idx_articles_created_id", "idx_articles_author_created"} {
+					if tx.Migrator().HasIndex(&domain.Article{}, name) {
+						continue
+					}
+					if err := tx.Migrator().CreateIndex(&domain.Article{}, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, name := range []string{"idx_articles_created_id", "idx_articles_author_created"} {
+					if err := tx.Migrator().DropIndex(&domain.Article{}, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// 用户角色、文章评论计数、评论与举报
+			ID: "20250101000003",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.User{}, &domain.Article{}, &domain.Comment{}, &domain.CommentFlag{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&domain.CommentFlag{}, &domain.Comment{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&domain.Article{}, "comment_count"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&domain.User{}, "role")
+			},
+		},
+		{
+			// 点赞、收藏与浏览量
+			ID: "20250101000004",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.Article{}, &domain.ArticleLike{}, &domain.Bookmark{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&domain.Bookmark{}, &domain.ArticleLike{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"like_count", "view_count"} {
+					if err := tx.Migrator().DropColumn(&domain.Article{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// 关注关系
+			ID: "20250101000005",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.Follow{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&domain.Follow{})
+			},
+		},
+		{
+			// 文章正文格式与摘要，并为已有文章生成摘要
+			ID: "20250101000006",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&domain.Article{}); err != nil {
+					return err
+				}
+
+				db := tx.Session(&gorm.Session{NewDB: true})
+				var articles []domain.Article
+				return tx.Model(&domain.Article{}).Select("id", "content", "content_format").
+					Where("excerpt IS NULL OR excerpt = ''").
+					FindInBatches(&articles, 200, func(_ *gorm.DB, _ int) error {
+						for _, article := range articles {
+							excerpt := render.Excerpt(article.ContentFormat, article.Content)
+							if err := db.Model(&domain.Article{}).Where("id = ?", article.ID).
+								UpdateColumn("excerpt", excerpt).Error; err != nil {
+								return err
+							}
+						}
+						return nil
+					}).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"content_format", "excerpt"} {
+					if err := tx.Migrator().DropColumn(&domain.Article{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// 文章 slug 与旧 slug 重定向，并为已有文章生成 slug
+			ID: "20250101000007",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&domain.Article{}, "slug") {
+					if err := tx.Migrator().AddColumn(&domain.Article{}, "Slug"); err != nil {
+						return err
+					}
+				}
+
+				var articles []domain.Article
+				if err := tx.Unscoped().Select("id", "title").
+					Where("slug IS NULL OR slug = ''").Order("id").Find(&articles).Error; err != nil {
+					return err
+				}
+				db := tx.Session(&gorm.Session{NewDB: true})
+				taken := func(candidate string) (bool, error) {
+					var count int64
+					err := db.Unscoped().Model(&domain.Article{}).Where("slug = ?", candidate).Count(&count).Error
+					return count > 0, err
+				}
+				for _, article := range articles {
+					s, err := slug.Unique(slug.Make(article.Title), "article", taken)
+					if err != nil {
+						return err
+					}
+					if err := db.Unscoped().Model(&domain.Article{}).Where("id = ?", article.ID).
+						UpdateColumn("slug", s).Error; err != nil {
+						return err
+					}
+				}
+
+				if !tx.Migrator().HasIndex(&domain.Article{}, "idx_articles_slug") {
+					if err := tx.Migrator().CreateIndex(&domain.Article{}, "idx_articles_slug"); err != nil {
+						return err
+					}
+				}
+				return tx.AutoMigrate(&domain.ArticleSlugRedirect{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&domain.ArticleSlugRedirect{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropIndex(&domain.Article{}, "idx_articles_slug"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&domain.Article{}, "slug")
+			},
+		},
+		{
+			// 文章附件与用户头像
+			ID: "20250101000008",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.User{}, &domain.Attachment{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&domain.Attachment{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"avatar_key", "avatar_thumb_key"} {
+					if err := tx.Migrator().DropColumn(&domain.User{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// 评论作者的外键约束：用户被彻底删除后，其评论以已删除状态保留以维持楼层结构
+			ID: "20250101000009",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasConstraint(&domain.Comment{}, "fk_comments_author") {
+					return nil
+				}
+				return tx.Migrator().DropConstraint(&domain.Comment{}, "fk_comments_author")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return nil
+			},
+		},
+		{
+			// 个人数据导出与账号注销请求
+			ID: "20250101000010",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.DataExport{}, &domain.ErasureRequest{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&domain.DataExport{}, &domain.ErasureRequest{})
+			},
+		},
+		{
+			// 文章批量导入使用的外部标识
+			ID: "20250101000011",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&domain.Article{}, "external_id") {
+					if err := tx.Migrator().AddColumn(&domain.Article{}, "ExternalID"); err != nil {
+						return err
+					}
+				}
+				if tx.Migrator().HasIndex(&domain.Article{}, "idx_articles_author_external") {
+					return nil
+				}
+				return tx.Migrator().CreateIndex(&domain.Article{}, "idx_articles_author_external")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropIndex(&domain.Article{}, "idx_articles_author_external"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&domain.Article{}, "external_id")
+			},
+		},
+		{
+			// 文章草稿状态与协作者
+			ID: "20250101000012",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&domain.Article{}, "status") {
+					if err := tx.Migrator().AddColumn(&domain.Article{}, "Status"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&domain.Article{}, "idx_articles_status") {
+					if err := tx.Migrator().CreateIndex(&domain.Article{}, "idx_articles_status"); err != nil {
+						return err
+					}
+				}
+				return tx.AutoMigrate(&domain.ArticleContributor{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&domain.ArticleContributor{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropIndex(&domain.Article{}, "idx_articles_status"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&domain.Article{}, "status")
+			},
+		},
+		{
+			// 文章系列
+			ID: "20250101000013",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&domain.Series{}, &domain.SeriesArticle{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&domain.SeriesArticle{}, &domain.Series{})
+			},
+		},
+		{
+			// 用户语言偏好
+			ID: "20250101000014",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&domain.User{}, "locale") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&domain.User{}, "Locale")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&domain.User{}, "locale")
+			},
+		},
 	})
 
 	return m.Migrate()
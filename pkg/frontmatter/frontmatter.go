@@ -0,0 +1,67 @@
+// Package frontmatter 读写带 YAML front matter 的 Markdown 文件
+//
+//	---
+//	title: 标题
+//	---
+//
+//	正文
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const delimiter = "---"
+
+// ErrMissing 文件不是以 front matter 开头
+var ErrMissing = errors.New("front matter not found")
+
+// Format 生成带 front matter 的文件内容，正文与 front matter 之间空一行，末尾追加换行
+func Format(meta interface{}, body string) ([]byte, error) {
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	buf.Write(data)
+	buf.WriteString(delimiter + "\n\n")
+	buf.WriteString(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Parse 将 front matter 解析到 meta，返回正文
+// 正文开头的一个空行和末尾的一个换行会被去掉，因此 Parse 可以还原 Format 的输入。
+func Parse(data []byte, meta interface{}) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	rest, ok := bytes.CutPrefix(data, []byte(delimiter+"\n"))
+	if !ok {
+		return "", ErrMissing
+	}
+
+	// 在 rest 前补一个换行，使空 front matter 也能匹配到结束分隔行
+	rest = append([]byte("\n"), rest...)
+	var head, body []byte
+	if end := bytes.Index(rest, []byte("\n"+delimiter+"\n")); end >= 0 {
+		head, body = rest[:end], rest[end+len(delimiter)+2:]
+	} else if bytes.HasSuffix(rest, []byte("\n"+delimiter)) {
+		head = rest[:len(rest)-len(delimiter)-1]
+	} else {
+		return "", errors.New("front matter is not closed")
+	}
+
+	if err := yaml.Unmarshal(head, meta); err != nil {
+		return "", err
+	}
+
+	body = bytes.TrimPrefix(body, []byte("\n"))
+	body = bytes.TrimSuffix(body, []byte("\n"))
+	return string(body), nil
+}
@@ -0,0 +1,65 @@
+// Package imaging 提供图片缩略图生成
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// 解码前检查的尺寸上限：压缩后很小的图片可能声明极大的尺寸，解码时占用大量内存
+const (
+	MaxSide   = 10000
+	MaxPixels = 25_000_000
+)
+
+// ErrImageTooLarge 图片尺寸超过 MaxSide 或 MaxPixels
+var ErrImageTooLarge = errors.New("imaging: image dimensions too large")
+
+// Thumbnail 将图片居中裁剪为正方形并缩放到 size×size
+// PNG / GIF 输出为 PNG（保留可能存在的透明通道），其余输出为 JPEG。返回图片数据和对应的 Content-Type。
+// 先只读取图片头部检查尺寸，超过上限时返回 ErrImageTooLarge，不会解码像素数据。
+func Thumbnail(r io.Reader, size int) ([]byte, string, error) {
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width > MaxSide || cfg.Height > MaxSide || cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	src, format, err := image.Decode(io.MultiReader(&head, r))
+	if err != nil {
+		return nil, "", err
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Point{
+		X: bounds.Min.X + (bounds.Dx()-side)/2,
+		Y: bounds.Min.Y + (bounds.Dy()-side)/2,
+	})
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
@@ -0,0 +1,301 @@
+// Package listquery 解析列表接口通用的排序、过滤与字段选择参数
+//
+//	sort=-updated_at,title
+//	filter[author_id]=1&filter[created_after]=2024-01-01
+//	fields=id,title,author.username
+//
+// 所有参数都必须在 Schema 白名单中声明，列名只来自 Schema，
+// 用户输入的值始终以占位符参数传给数据库。
+package listquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 参数错误的原因，可通过 errors.Is 判断
+var (
+	ErrUnsupportedSort   = errors.New("unsupported sort field")
+	ErrUnsupportedFilter = errors.New("unsupported filter")
+	ErrInvalidFilter     = errors.New("invalid value for filter")
+	ErrUnsupportedField  = errors.New("unsupported field")
+)
+
+// ParamError 查询参数错误，Name 为出错的排序字段、过滤参数或选择字段名
+type ParamError struct {
+	Err  error
+	Name string
+}
+
+func (e *ParamError) Error() string {
+	if e.Err == ErrInvalidFilter {
+		return e.Err.Error() + " " + e.Name
+	}
+	return e.Err.Error() + ": " + e.Name
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Filter 描述一个可用的过滤参数
+type Filter struct {
+	Column string
+	Op     string // =、>=、<= 、<、>
+	Parse  func(string) (interface{}, error)
+}
+
+// Relation 描述一个可在 fields 中选择字段的关联对象
+type Relation struct {
+	Preload    string            // GORM 关联名，如 "Author"
+	ForeignKey string            // 主表上的外键列，选择字段时会自动带上
+	Fields     map[string]string // JSON 字段名 -> 列名
+}
+
+// Schema 列表接口允许的排序、过滤与字段白名单
+type Schema struct {
+	Sorts     map[string]string // 参数名 -> 列名
+	Filters   map[string]Filter
+	Fields    map[string]string // JSON 字段名 -> 列名
+	Relations map[string]Relation
+}
+
+type sortField struct {
+	column string
+	desc   bool
+}
+
+type filterValue struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+// Query 解析后的列表查询参数
+type Query struct {
+	schema    *Schema
+	sorts     []sortField
+	filters   []filterValue
+	fields    []string
+	relations map[string][]string
+}
+
+// Parse 按 Schema 解析 URL 查询参数，遇到未声明的参数名或非法值时返回错误
+func Parse(values url.Values, schema *Schema) (*Query, error) {
+	q := &Query{schema: schema, relations: make(map[string][]string)}
+
+	if v := values.Get("sort"); v != "" {
+		for _, name := range splitList(v) {
+			desc := strings.HasPrefix(name, "-")
+			name = strings.TrimPrefix(name, "-")
+			column, ok := schema.Sorts[name]
+			if !ok {
+				return nil, &ParamError{ErrUnsupportedSort, name}
+			}
+			q.sorts = append(q.sorts, sortField{column: column, desc: desc})
+		}
+	}
+
+	for key, vals := range values {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := key[len("filter[") : len(key)-1]
+		filter, ok := schema.Filters[name]
+		if !ok {
+			return nil, &ParamError{ErrUnsupportedFilter, name}
+		}
+		value, err := filter.Parse(vals[0])
+		if err != nil {
+			return nil, &ParamError{ErrInvalidFilter, name}
+		}
+		q.filters = append(q.filters, filterValue{column: filter.Column, op: filter.Op, value: value})
+	}
+
+	if v := values.Get("fields"); v != "" {
+		for _, name := range splitList(v) {
+			if rel, field, ok := strings.Cut(name, "."); ok {
+				relation, ok := schema.Relations[rel]
+				if !ok {
+					return nil, &ParamError{ErrUnsupportedField, name}
+				}
+				if _, ok := relation.Fields[field]; !ok {
+					return nil, &ParamError{ErrUnsupportedField, name}
+				}
+				q.relations[rel] = append(q.relations[rel], field)
+				continue
+			}
+			if _, ok := schema.Fields[name]; !ok {
+				return nil, &ParamError{ErrUnsupportedField, name}
+			}
+			q.fields = append(q.fields, name)
+		}
+	}
+
+	return q, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HasSort 是否指定了排序
+func (q *Query) HasSort() bool {
+	return q != nil && len(q.sorts) > 0
+}
+
+// HasFields 是否指定了字段选择
+func (q *Query) HasFields() bool {
+	return q != nil && (len(q.fields) > 0 || len(q.relations) > 0)
+}
+
+// Filter 过滤条件 scope，可同时用于计数和查询
+func (q *Query) Filter(db *gorm.DB) *gorm.DB {
+	if q == nil {
+		return db
+	}
+	for _, f := range q.filters {
+		db = db.Where(fmt.Sprintf("%s %s ?", f.column, f.op), f.value)
+	}
+	return db
+}
+
+// Sort 排序 scope，最后总是以 id 作为次级排序保证结果稳定
+func (q *Query) Sort(db *gorm.DB) *gorm.DB {
+	if !q.HasSort() {
+		return db
+	}
+	hasID := false
+	for _, s := range q.sorts {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.column}, Desc: s.desc})
+		hasID = hasID || s.column == "id"
+	}
+	if !hasID {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: q.sorts[0].desc})
+	}
+	return db
+}
+
+// Select 字段选择 scope，只查询需要的列并按需预加载关联
+// required 为调用方必须读取的列（例如游标分页依赖的 created_at）。
+func (q *Query) Select(required ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !q.HasFields() {
+			return db
+		}
+
+		columns := []string{"id"}
+		columns = append(columns, required...)
+		for _, name := range q.fields {
+			columns = append(columns, q.schema.Fields[name])
+		}
+		for rel, fields := range q.relations {
+			relation := q.schema.Relations[rel]
+			columns = append(columns, relation.ForeignKey)
+
+			relColumns := []string{"id"}
+			for _, name := range fields {
+				relColumns = append(relColumns, relation.Fields[name])
+			}
+			db = db.Preload(relation.Preload, func(tx *gorm.DB) *gorm.DB {
+				return tx.Select(dedupe(relColumns))
+			})
+		}
+		return db.Select(dedupe(columns))
+	}
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]struct{}, len(items))
+	out := items[:0]
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+// Project 按 fields 裁剪响应数据，v 可以是单个对象或对象列表
+// 未指定 fields 时原样返回。
+func (q *Query) Project(v interface{}) (interface{}, error) {
+	if !q.HasFields() {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	switch val := decoded.(type) {
+	case []interface{}:
+		for i, item := range val {
+			if obj, ok := item.(map[string]interface{}); ok {
+				val[i] = q.prune(obj)
+			}
+		}
+		return val, nil
+	case map[string]interface{}:
+		return q.prune(val), nil
+	default:
+		return decoded, nil
+	}
+}
+
+func (q *Query) prune(obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(q.fields)+len(q.relations))
+	for _, name := range q.fields {
+		if v, ok := obj[name]; ok {
+			out[name] = v
+		}
+	}
+	for rel, fields := range q.relations {
+		nested, ok := obj[rel].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sub := make(map[string]interface{}, len(fields))
+		for _, name := range fields {
+			if v, ok := nested[name]; ok {
+				sub[name] = v
+			}
+		}
+		out[rel] = sub
+	}
+	return out
+}
+
+// ParseUint 解析无符号整数过滤值
+func ParseUint(s string) (interface{}, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	return uint(v), err
+}
+
+// ParseTime 解析时间过滤值，支持 RFC3339 和 2006-01-02 两种格式
+func ParseTime(s string) (interface{}, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
@@ -0,0 +1,169 @@
+package listquery_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/pkg/listquery"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+var schema = &listquery.Schema{
+	Sorts: map[string]string{
+		"id":      "id",
+		"title":   "title",
+		"created": "created_at",
+	},
+	Filters: map[string]listquery.Filter{
+		"author_id":     {Column: "author_id", Op: "=", Parse: listquery.ParseUint},
+		"created_after": {Column: "created_at", Op: ">=", Parse: listquery.ParseTime},
+	},
+	Fields: map[string]string{
+		"id":    "id",
+		"title": "title",
+	},
+	Relations: map[string]listquery.Relation{
+		"author": {Preload: "Author", ForeignKey: "author_id", Fields: map[string]string{"username": "username"}},
+	},
+}
+
+type item struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	AuthorID  uint   `json:"author_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		err       error
+		errName   string
+		hasSort   bool
+		hasFields bool
+	}{
+		{name: "empty", query: ""},
+		{name: "sort", query: "sort=-created,title", hasSort: true},
+		{name: "sort with blanks", query: "sort=title,,%20", hasSort: true},
+		{name: "filters", query: "filter[author_id]=3&filter[created_after]=2024-01-01"},
+		{name: "fields", query: "fields=id,title,author.username", hasFields: true},
+		{name: "unknown sort", query: "sort=password", err: listquery.ErrUnsupportedSort, errName: "password"},
+		{name: "sort by column name", query: "sort=created_at", err: listquery.ErrUnsupportedSort, errName: "created_at"},
+		{name: "unknown filter", query: "filter[password]=x", err: listquery.ErrUnsupportedFilter, errName: "password"},
+		{name: "invalid filter value", query: "filter[author_id]=abc", err: listquery.ErrInvalidFilter, errName: "author_id"},
+		{name: "invalid time", query: "filter[created_after]=yesterday", err: listquery.ErrInvalidFilter, errName: "created_after"},
+		{name: "unknown field", query: "fields=id,password", err: listquery.ErrUnsupportedField, errName: "password"},
+		{name: "unknown relation", query: "fields=editor.username", err: listquery.ErrUnsupportedField, errName: "editor.username"},
+		{name: "unknown relation field", query: "fields=author.email", err: listquery.ErrUnsupportedField, errName: "author.email"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := listquery.Parse(values, schema)
+			if tc.err != nil {
+				var paramErr *listquery.ParamError
+				if !errors.Is(err, tc.err) || !errors.As(err, &paramErr) || paramErr.Name != tc.errName {
+					t.Fatalf("expected %v for %q, got %v", tc.err, tc.errName, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.HasSort() != tc.hasSort || q.HasFields() != tc.hasFields {
+				t.Fatalf("unexpected query: sort=%v fields=%v", q.HasSort(), q.HasFields())
+			}
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		query string
+		sql   string
+		vars  []interface{}
+	}{
+		{
+			query: "",
+			sql:   "SELECT * FROM `items`",
+		},
+		{
+			query: "sort=-created",
+			sql:   "SELECT * FROM `items` ORDER BY `created_at` DESC,`id` DESC",
+		},
+		{
+			query: "sort=title,-id",
+			sql:   "SELECT * FROM `items` ORDER BY `title`,`id` DESC",
+		},
+		{
+			// 过滤值始终作为占位符参数传入
+			query: "filter[author_id]=3",
+			sql:   "SELECT * FROM `items` WHERE author_id = ?",
+			vars:  []interface{}{uint(3)},
+		},
+		{
+			query: "fields=title",
+			sql:   "SELECT `id`,`title` FROM `items`",
+		},
+	}
+	for _, tc := range cases {
+		values, err := url.ParseQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := listquery.Parse(values, schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt := db.Model(&item{}).Scopes(q.Filter, q.Sort, q.Select()).Find(&[]item{}).Statement
+		if got := strings.TrimSpace(stmt.SQL.String()); got != tc.sql {
+			t.Errorf("%q: expected %s, got %s", tc.query, tc.sql, got)
+		}
+		if len(tc.vars) > 0 && !reflect.DeepEqual(stmt.Vars, tc.vars) {
+			t.Errorf("%q: expected vars %v, got %v", tc.query, tc.vars, stmt.Vars)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	values, _ := url.ParseQuery("fields=id,title,author.username")
+	q, err := listquery.Parse(values, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type withAuthor struct {
+		item
+		Author map[string]string `json:"author"`
+	}
+	got, err := q.Project([]withAuthor{{
+		item:   item{ID: 1, Title: "Hello", Content: "secret"},
+		Author: map[string]string{"username": "alice", "email": "alice@example.com"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{map[string]interface{}{
+		"id":     float64(1),
+		"title":  "Hello",
+		"author": map[string]interface{}{"username": "alice"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
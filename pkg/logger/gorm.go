@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger 将 GORM 日志写入全局日志：
+// 执行出错的 SQL 记为 error（记录不存在除外），超过慢查询阈值的记为 warn，其余 SQL 只在 debug 级别输出。
+// 记录的 SQL 只保留 ? 占位符，不包含绑定的参数值，避免密码哈希、邮箱、令牌等写入日志。
+type GormLogger struct {
+	slowThreshold time.Duration
+	level         gormlogger.LogLevel
+}
+
+// NewGormLogger slowThreshold 为慢查询阈值，0 表示不记录慢查询
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{slowThreshold: slowThreshold, level: gormlogger.Info}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		Info(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		Warn(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		Error(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// ParamsFilter 丢弃绑定参数，GORM 生成日志中的 SQL 时只保留占位符
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		Error(ctx, "sql failed", sqlAttrs(sql, rows, elapsed), Err(err))
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		Warn(ctx, "slow sql", sqlAttrs(sql, rows, elapsed), slog.Duration("threshold", l.slowThreshold))
+	case l.level >= gormlogger.Info && Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		Debug(ctx, "sql", sqlAttrs(sql, rows, elapsed))
+	}
+}
+
+func sqlAttrs(sql string, rows int64, elapsed time.Duration) slog.Attr {
+	return slog.Group("sql", slog.String("query", sql), slog.Int64("rows", rows), slog.Duration("elapsed", elapsed))
+}
@@ -1,47 +1,176 @@
+// Package logger 基于 log/slog 的结构化日志。
+//
+// 日志函数的第一个参数为 context，请求处理过程中传入请求上下文，
+// NewContext 与 AddAttrs 附加到上下文的字段（请求ID、用户ID、路由等）会自动写入每一行日志。
 package logger
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
-var (
-	infoLogger  *log.Logger
-	errorLogger *log.Logger
+// 日志格式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
-// Init 初始化日志
-func Init() {
-	infoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	errorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+// Options 日志配置
+type Options struct {
+	Level  string    // debug / info / warn / error，默认 info
+	Format string    // text / json，默认 text
+	Output io.Writer // 默认 os.Stdout
+}
+
+var defaultLogger = slog.New(&contextHandler{slog.NewTextHandler(os.Stdout, nil)})
+
+// Init 按配置初始化全局日志，同时设置为 slog 的默认日志
+func Init(opts Options) error {
+	l, err := New(opts)
+	if err != nil {
+		return err
+	}
+	defaultLogger = l
+	slog.SetDefault(l)
+	return nil
+}
+
+// New 按配置创建日志
+func New(opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(out, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(out, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s", opts.Format)
+	}
+	return slog.New(&contextHandler{handler}), nil
+}
+
+// ParseLevel 解析日志级别，为空时返回 info
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("unsupported log level: %s", s)
+	}
+	return level, nil
+}
+
+// L 返回全局日志
+func L() *slog.Logger {
+	return defaultLogger
+}
+
+// Enabled 全局日志是否输出指定级别
+func Enabled(ctx context.Context, level slog.Level) bool {
+	return defaultLogger.Enabled(ctx, level)
+}
+
+// Debug 记录调试日志，args 为交替的键值对或 slog.Attr
+func Debug(ctx context.Context, msg string, args ...any) {
+	defaultLogger.DebugContext(ctx, msg, args...)
 }
 
 // Info 记录信息日志
-func Info(v ...interface{}) {
-	infoLogger.Println(v...)
+func Info(ctx context.Context, msg string, args ...any) {
+	defaultLogger.InfoContext(ctx, msg, args...)
 }
 
-// Infof 格式化记录信息日志
-func Infof(format string, v ...interface{}) {
-	infoLogger.Printf(format, v...)
+// Warn 记录警告日志
+func Warn(ctx context.Context, msg string, args ...any) {
+	defaultLogger.WarnContext(ctx, msg, args...)
 }
 
 // Error 记录错误日志
-func Error(v ...interface{}) {
-	errorLogger.Println(v...)
+func Error(ctx context.Context, msg string, args ...any) {
+	defaultLogger.ErrorContext(ctx, msg, args...)
+}
+
+// Fatal 记录错误日志并退出进程
+func Fatal(ctx context.Context, msg string, args ...any) {
+	defaultLogger.ErrorContext(ctx, msg, args...)
+	os.Exit(1)
+}
+
+// Err 错误字段，统一使用 error 作为键
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+// fields 附加到上下文的日志字段，请求处理过程中可以继续追加，例如认证后追加用户ID
+type fields struct {
+	start time.Time
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+type fieldsKey struct{}
+
+// NewContext 返回携带日志字段的上下文，之后使用该上下文记录的日志都会带上这些字段，
+// 以及距 NewContext 调用经过的时间 latency
+func NewContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &fields{start: time.Now(), attrs: attrs})
+}
+
+// AddAttrs 向上下文追加日志字段，上下文未经 NewContext 创建时忽略
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	f := fieldsFrom(ctx)
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.attrs = append(f.attrs, attrs...)
+	f.mu.Unlock()
+}
+
+func fieldsFrom(ctx context.Context) *fields {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(fieldsKey{}).(*fields)
+	return f
+}
+
+// contextHandler 将上下文中的日志字段写入每条日志
+type contextHandler struct {
+	slog.Handler
 }
 
-// Errorf 格式化记录错误日志
-func Errorf(format string, v ...interface{}) {
-	errorLogger.Printf(format, v...)
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f := fieldsFrom(ctx); f != nil {
+		f.mu.Lock()
+		r.AddAttrs(f.attrs...)
+		f.mu.Unlock()
+		r.AddAttrs(slog.Duration("latency", time.Since(f.start)))
+	}
+	return h.Handler.Handle(ctx, r)
 }
 
-// Fatal 记录致命错误并退出
-func Fatal(v ...interface{}) {
-	errorLogger.Fatal(v...)
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
 }
 
-// Fatalf 格式化记录致命错误并退出
-func Fatalf(format string, v ...interface{}) {
-	errorLogger.Fatalf(format, v...)
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
 }
@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrCursorConflict = errors.New("after and before cannot be used together")
+)
+
+// Cursor 指向按 (created_at, id) 倒序排列的列表中的一条记录
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+type cursorPayload struct {
+	T  int64 `json:"t"`
+	ID uint  `json:"id"`
+}
+
+// Encode 将游标编码为对客户端不透明的字符串
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(cursorPayload{T: c.CreatedAt.UnixNano(), ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析客户端传入的游标字符串
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil || p.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	// 统一使用 UTC，与数据库中以 UTC 保存的时间比较，不受进程时区影响
+	return &Cursor{CreatedAt: time.Unix(0, p.T).UTC(), ID: p.ID}, nil
+}
+
+// CursorPage 游标分页参数，After 与 Before 至多设置一个
+// After 获取游标之后（更旧）的记录，Before 获取游标之前（更新）的记录。
+type CursorPage struct {
+	After  *Cursor
+	Before *Cursor
+	Limit  int
+}
+
+// NewCursorPage 解析 after / before 参数并构造分页参数
+func NewCursorPage(after, before string, limit int) (CursorPage, error) {
+	page := CursorPage{Limit: limit}
+	if after != "" && before != "" {
+		return page, ErrCursorConflict
+	}
+
+	var err error
+	if after != "" {
+		if page.After, err = DecodeCursor(after); err != nil {
+			return page, err
+		}
+	}
+	if before != "" {
+		if page.Before, err = DecodeCursor(before); err != nil {
+			return page, err
+		}
+	}
+	return page, nil
+}
+
+// PageInfo 游标分页的响应信息
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Trim 处理多查询一条（Limit+1）的结果：裁掉多余记录并生成分页信息
+// items 需按 (created_at, id) 倒序排列。
+func Trim[T any](items []T, page CursorPage, cursorOf func(T) Cursor) ([]T, PageInfo) {
+	var info PageInfo
+
+	info.HasMore = len(items) > page.Limit
+	if info.HasMore {
+		if page.Before != nil {
+			// 向前翻页时多出来的一条是离游标最远（最新）的记录
+			items = items[len(items)-page.Limit:]
+		} else {
+			items = items[:page.Limit]
+		}
+	}
+	if len(items) == 0 {
+		return items, info
+	}
+
+	first, last := cursorOf(items[0]), cursorOf(items[len(items)-1])
+	if page.Before != nil {
+		// 游标本身指向的记录更旧，因此总是存在下一页
+		info.NextCursor = last.Encode()
+		if info.HasMore {
+			info.PrevCursor = first.Encode()
+		}
+	} else {
+		if info.HasMore {
+			info.NextCursor = last.Encode()
+		}
+		if page.After != nil {
+			info.PrevCursor = first.Encode()
+		}
+	}
+	return items, info
+}
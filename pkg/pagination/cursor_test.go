@@ -0,0 +1,124 @@
+package pagination_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Anning01/user-management/pkg/pagination"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cases := []pagination.Cursor{
+		{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), ID: 1},
+		{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("UTC+8", 8*60*60)), ID: 42},
+		{CreatedAt: time.Unix(0, 0), ID: 1<<32 - 1},
+	}
+	for _, c := range cases {
+		decoded, err := pagination.DecodeCursor(c.Encode())
+		if err != nil {
+			t.Fatalf("decode %+v: %v", c, err)
+		}
+		// 解码后统一为 UTC，表示的时刻不变
+		if !decoded.CreatedAt.Equal(c.CreatedAt) || decoded.CreatedAt.Location() != time.UTC || decoded.ID != c.ID {
+			t.Errorf("expected %+v, got %+v", c, decoded)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	cases := map[string]string{
+		"not base64":   "!!!",
+		"not json":     "bm90IGpzb24",
+		"missing id":   "eyJ0IjoxfQ",
+		"padded":       "eyJ0IjoxLCJpZCI6MX0=",
+		"empty object": "e30",
+	}
+	for name, s := range cases {
+		if _, err := pagination.DecodeCursor(s); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	cursor := pagination.Cursor{CreatedAt: time.Now(), ID: 7}.Encode()
+
+	cases := []struct {
+		name          string
+		after, before string
+		err           error
+		hasAfter      bool
+		hasBefore     bool
+	}{
+		{name: "none"},
+		{name: "after", after: cursor, hasAfter: true},
+		{name: "before", before: cursor, hasBefore: true},
+		{name: "both", after: cursor, before: cursor, err: pagination.ErrCursorConflict},
+		{name: "invalid after", after: "!!!", err: pagination.ErrInvalidCursor},
+		{name: "invalid before", before: "!!!", err: pagination.ErrInvalidCursor},
+	}
+	for _, tc := range cases {
+		page, err := pagination.NewCursorPage(tc.after, tc.before, 10)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.err, err)
+			continue
+		}
+		if tc.err == nil && ((page.After != nil) != tc.hasAfter || (page.Before != nil) != tc.hasBefore || page.Limit != 10) {
+			t.Errorf("%s: unexpected page %+v", tc.name, page)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 按 (created_at, id) 倒序排列的记录
+	items := []uint{5, 4, 3, 2, 1}
+	cursorOf := func(id uint) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: base.Add(time.Duration(id) * time.Minute), ID: id}
+	}
+	at := func(id uint) *pagination.Cursor {
+		c := cursorOf(id)
+		return &c
+	}
+
+	cases := []struct {
+		name       string
+		items      []uint
+		page       pagination.CursorPage
+		want       []uint
+		next, prev uint
+		hasMore    bool
+	}{
+		{name: "first page", items: items[:3], page: pagination.CursorPage{Limit: 2}, want: []uint{5, 4}, next: 4, hasMore: true},
+		{name: "last page", items: items[:2], page: pagination.CursorPage{Limit: 2}, want: []uint{5, 4}},
+		{name: "after", items: []uint{3, 2, 1}, page: pagination.CursorPage{After: at(4), Limit: 2}, want: []uint{3, 2}, next: 2, prev: 3, hasMore: true},
+		{name: "after last page", items: []uint{1}, page: pagination.CursorPage{After: at(2), Limit: 2}, want: []uint{1}, prev: 1},
+		{name: "before", items: []uint{5, 4, 3}, page: pagination.CursorPage{Before: at(2), Limit: 2}, want: []uint{4, 3}, next: 3, prev: 4, hasMore: true},
+		{name: "before first page", items: []uint{5}, page: pagination.CursorPage{Before: at(4), Limit: 2}, want: []uint{5}, next: 5},
+		{name: "empty", items: nil, page: pagination.CursorPage{Limit: 2}, want: nil},
+	}
+	for _, tc := range cases {
+		got, info := pagination.Trim(tc.items, tc.page, cursorOf)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+				break
+			}
+		}
+		if info.HasMore != tc.hasMore || info.NextCursor != encode(cursorOf, tc.next) || info.PrevCursor != encode(cursorOf, tc.prev) {
+			t.Errorf("%s: unexpected page info %+v", tc.name, info)
+		}
+	}
+}
+
+func encode(cursorOf func(uint) pagination.Cursor, id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return cursorOf(id).Encode()
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache 缓存文章的渲染结果
+// 以文章ID为键，并记录渲染时文章的更新时间，更新时间变化后缓存自动失效。
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[uint]cacheEntry
+}
+
+type cacheEntry struct {
+	version time.Time
+	result  *Result
+}
+
+// NewCache 创建最多保存 size 条结果的缓存
+func NewCache(size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		size:    size,
+		entries: make(map[uint]cacheEntry, size),
+	}
+}
+
+// Get 读取缓存，version 与缓存时不一致视为未命中
+func (c *Cache) Get(id uint, version time.Time) (*Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok || !entry.version.Equal(version) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// Put 写入缓存，已满时随机淘汰一条
+func (c *Cache) Put(id uint, version time.Time, result *Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= c.size {
+		for key := range c.entries {
+			delete(c.entries, key)
+			break
+		}
+	}
+	c.entries[id] = cacheEntry{version: version, result: result}
+}
+
+// Delete 使指定文章的缓存失效
+func (c *Cache) Delete(id uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
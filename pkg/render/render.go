@@ -0,0 +1,192 @@
+// Package render 将文章正文渲染为经过白名单过滤的 HTML，并生成目录和摘要
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// 支持的正文格式
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// ExcerptLength 自动摘要的最大字符数
+const ExcerptLength = 200
+
+// Heading 目录中的一个标题
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML    string
+	TOC     []Heading
+	Excerpt string
+}
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	// policy 在 UGC 白名单基础上允许标题锚点，供目录跳转使用
+	policy = func() *bluemonday.Policy {
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("id").Matching(bluemonday.SpaceSeparatedTokens).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+		return p
+	}()
+
+	stripPolicy = bluemonday.StrictPolicy()
+)
+
+// ValidFormat 判断正文格式是否受支持
+func ValidFormat(format string) bool {
+	return format == FormatPlain || format == FormatMarkdown
+}
+
+// Render 按格式渲染正文，输出始终经过 HTML 白名单过滤
+func Render(format, content string) (*Result, error) {
+	var (
+		raw string
+		toc []Heading
+		err error
+	)
+
+	switch format {
+	case FormatMarkdown:
+		raw, toc, err = renderMarkdown(content)
+		if err != nil {
+			return nil, err
+		}
+	case FormatPlain, "":
+		raw = renderPlain(content)
+	default:
+		return nil, fmt.Errorf("unsupported content format: %s", format)
+	}
+
+	sanitized := policy.Sanitize(raw)
+	return &Result{
+		HTML:    sanitized,
+		TOC:     toc,
+		Excerpt: excerptFromHTML(sanitized),
+	}, nil
+}
+
+// Excerpt 生成正文的纯文本摘要
+func Excerpt(format, content string) string {
+	result, err := Render(format, content)
+	if err != nil {
+		return truncate(collapseSpace(content), ExcerptLength)
+	}
+	return result.Excerpt
+}
+
+func renderMarkdown(content string) (string, []Heading, error) {
+	source := []byte(content)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+
+	var toc []Heading
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		toc = append(toc, Heading{
+			Level: heading.Level,
+			ID:    string(idBytes),
+			Text:  nodeText(heading, source),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return "", nil, err
+	}
+	return buf.String(), toc, nil
+}
+
+// nodeText 提取节点下的纯文本
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := child.(type) {
+		case *ast.Text:
+			sb.Write(t.Segment.Value(source))
+		case *ast.String:
+			sb.Write(t.Value)
+		case *ast.CodeSpan:
+			for c := t.FirstChild(); c != nil; c = c.NextSibling() {
+				if seg, ok := c.(*ast.Text); ok {
+					sb.Write(seg.Segment.Value(source))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// renderPlain 纯文本按空行分段，段内换行转为 <br>
+func renderPlain(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var sb strings.Builder
+	for _, para := range strings.Split(content, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		lines := strings.Split(para, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.Join(lines, "<br>\n"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+func excerptFromHTML(s string) string {
+	// 块级标签替换为空格，避免相邻段落的文字粘连
+	s = strings.NewReplacer("</p>", " </p>", "<br>", " ", "</li>", " </li>", "</h", " </h").Replace(s)
+	return truncate(collapseSpace(html.UnescapeString(stripPolicy.Sanitize(s))), ExcerptLength)
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
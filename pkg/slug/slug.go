@@ -0,0 +1,138 @@
+// Package slug 根据标题生成 URL 友好的短标识，中文转换为拼音
+package slug
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength slug 的最大长度
+const MaxLength = 80
+
+const (
+	// maxNumbered 依次追加数字后缀的最大尝试次数，超过后改用随机后缀
+	maxNumbered = 10
+	// maxRandom 随机后缀的最大尝试次数
+	maxRandom = 5
+)
+
+// ErrNoUniqueSlug 多次尝试后仍未找到未被占用的 slug
+var ErrNoUniqueSlug = errors.New("slug: no unique slug available")
+
+var (
+	pattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	pyArgs  = pinyin.NewArgs()
+)
+
+// Make 根据标题生成 slug：字母数字转小写，汉字转为不带声调的拼音，其余字符作为分隔符
+// 无法生成任何内容时返回空字符串。
+func Make(title string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	// 先分解带音调的拉丁字母（é → e + ́），再丢弃组合符号
+	for _, r := range norm.NFD.String(strings.ToLower(title)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pyArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return truncate(strings.Join(words, "-"))
+}
+
+// Valid 判断 slug 格式是否合法：小写字母、数字，以单个连字符分隔
+func Valid(s string) bool {
+	return len(s) <= MaxLength && pattern.MatchString(s)
+}
+
+// truncate 按连字符边界截断到 MaxLength 以内
+func truncate(s string) string {
+	return truncateTo(s, MaxLength)
+}
+
+// truncateTo 按连字符边界截断到 max 以内，单个词超过 max 时直接截断
+func truncateTo(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "-")
+}
+
+// Unique 以 base 为基础生成未被占用的 slug，冲突时依次追加 -2、-3……，
+// 尝试若干次后改用随机后缀，避免大量同名标题时逐个查询。
+// base 为空时使用 fallback，此时冲突后直接使用随机后缀。追加后缀时会截断 base，保证结果不超过 MaxLength。
+func Unique(base, fallback string, taken func(string) (bool, error)) (string, error) {
+	numbered := maxNumbered
+	if base == "" {
+		base = fallback
+		numbered = 1
+	}
+
+	try := func(candidate string) (bool, error) {
+		used, err := taken(candidate)
+		return err == nil && !used, err
+	}
+
+	if ok, err := try(base); ok || err != nil {
+		return base, err
+	}
+	for i := 2; i <= numbered; i++ {
+		candidate := withSuffix(base, strconv.Itoa(i))
+		if ok, err := try(candidate); ok || err != nil {
+			return candidate, err
+		}
+	}
+	for i := 0; i < maxRandom; i++ {
+		candidate := withSuffix(base, randomSuffix())
+		if ok, err := try(candidate); ok || err != nil {
+			return candidate, err
+		}
+	}
+	return "", ErrNoUniqueSlug
+}
+
+// withSuffix 追加 -suffix，必要时截断 base 以留出后缀的长度
+func withSuffix(base, suffix string) string {
+	base = truncateTo(base, MaxLength-len(suffix)-1)
+	if base == "" {
+		return suffix
+	}
+	return base + "-" + suffix
+}
+
+func randomSuffix() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
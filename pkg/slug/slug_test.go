@@ -0,0 +1,130 @@
+package slug_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Anning01/user-management/pkg/slug"
+)
+
+func TestMake(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":          "hello-world",
+		"  Go   1.24 released  ": "go-1-24-released",
+		"Café à la crème":        "cafe-a-la-creme",
+		"你好 世界":                  "ni-hao-shi-jie",
+		"Go 语言入门":                "go-yu-yan-ru-men",
+		"!!!":                    "",
+		"":                       "",
+	}
+	for title, want := range cases {
+		if got := slug.Make(title); got != want {
+			t.Errorf("Make(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	long := slug.Make(strings.Repeat("word ", 30))
+	if len(long) > slug.MaxLength || !slug.Valid(long) || strings.HasSuffix(long, "-") {
+		t.Errorf("expected long title to be truncated at a word boundary, got %q", long)
+	}
+}
+
+func TestValid(t *testing.T) {
+	cases := map[string]bool{
+		"hello-world":                         true,
+		"go-1-24":                             true,
+		"Hello":                               false,
+		"hello--world":                        false,
+		"-hello":                              false,
+		"hello_world":                         false,
+		"":                                    false,
+		strings.Repeat("a", slug.MaxLength):   true,
+		strings.Repeat("a", slug.MaxLength+1): false,
+	}
+	for s, want := range cases {
+		if got := slug.Valid(s); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	randomSuffix := regexp.MustCompile(`-[0-9a-f]{8}$`)
+	long := strings.Repeat("a", slug.MaxLength)
+
+	cases := []struct {
+		name     string
+		base     string
+		fallback string
+		taken    func(string) bool
+		want     string
+		random   bool
+		err      error
+	}{
+		{name: "free", base: "hello", taken: none, want: "hello"},
+		{name: "numbered", base: "hello", taken: set("hello", "hello-2"), want: "hello-3"},
+		{name: "fallback", base: "", fallback: "article", taken: none, want: "article"},
+		{name: "fallback taken uses random suffix", base: "", fallback: "article", taken: set("article"), random: true},
+		{name: "numbered exhausted uses random suffix", base: "hello", taken: numbered("hello"), random: true},
+		{name: "suffix keeps max length", base: long, taken: set(long), want: strings.Repeat("a", slug.MaxLength-2) + "-2"},
+		{name: "all taken", base: "hello", taken: func(string) bool { return true }, err: slug.ErrNoUniqueSlug},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := slug.Unique(tc.base, tc.fallback, func(s string) (bool, error) { return tc.taken(s), nil })
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if len(got) > slug.MaxLength || !slug.Valid(got) {
+				t.Fatalf("invalid slug %q", got)
+			}
+			if tc.random {
+				if !randomSuffix.MatchString(got) || tc.taken(got) {
+					t.Fatalf("expected a free slug with a random suffix, got %q", got)
+				}
+				return
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUniqueLookupError(t *testing.T) {
+	lookupErr := errors.New("database unavailable")
+	calls := 0
+	_, err := slug.Unique("hello", "", func(string) (bool, error) {
+		calls++
+		return false, lookupErr
+	})
+	if !errors.Is(err, lookupErr) || calls != 1 {
+		t.Fatalf("expected lookup error after one call, got %v after %d calls", err, calls)
+	}
+}
+
+func none(string) bool { return false }
+
+func set(taken ...string) func(string) bool {
+	m := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		m[s] = true
+	}
+	return func(s string) bool { return m[s] }
+}
+
+// numbered 占用 base 以及所有数字后缀的 slug
+func numbered(base string) func(string) bool {
+	return func(s string) bool {
+		if s == base {
+			return true
+		}
+		suffix, ok := strings.CutPrefix(s, base+"-")
+		return ok && suffix != "" && strings.Trim(suffix, "0123456789") == ""
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+
+// Signer 使用 HMAC-SHA256 为下载地址签名
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// NewRandomSigner 使用随机生成的密钥签名，未配置签名密钥时使用。
+// 密钥只在当前进程内有效，重启后或多实例部署时其他进程签发的地址会失效。
+func NewRandomSigner() (*Signer, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &Signer{secret: secret}, nil
+}
+
+// Sign 返回包含 expires 与 signature 参数的查询串
+func (s *Signer) Sign(key string, expiresAt time.Time) url.Values {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return url.Values{
+		"expires":   {expires},
+		"signature": {s.mac(key, expires)},
+	}
+}
+
+// Verify 校验签名是否有效且未过期
+func (s *Signer) Verify(key, expires, signature string) error {
+	ts, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > ts {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(s.mac(key, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Signer) mac(key, expires string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(key))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(expires))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,83 @@
+// Package storage 提供文件存储抽象，支持本地文件系统和 S3 兼容的对象存储
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// 支持的存储驱动
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// ObjectInfo 对象元数据
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage 文件存储接口
+// key 使用 / 分隔的相对路径，如 avatars/1/abc.png。
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL 生成带有效期的下载地址
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// Options 创建存储时的配置
+type Options struct {
+	Driver string
+
+	// 本地存储
+	LocalDir  string
+	PublicURL string // 下载地址前缀，为空时使用相对路径
+	Signer    *Signer
+
+	// S3 兼容存储
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// New 根据驱动名称创建存储
+func New(opts Options) (Storage, error) {
+	switch opts.Driver {
+	case DriverLocal, "":
+		return NewLocalStorage(opts.LocalDir, opts.PublicURL, opts.Signer)
+	case DriverS3:
+		return NewS3Storage(opts.Endpoint, opts.Region, opts.Bucket, opts.AccessKey, opts.SecretKey, opts.UseSSL)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", opts.Driver)
+	}
+}
+
+// CleanKey 规范化 key，拒绝绝对路径和越出根目录的路径
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}